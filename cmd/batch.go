@@ -0,0 +1,140 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+)
+
+// fileResult holds the buffered output of a file parsed by parseFiles until
+// the files before it have been written.
+type fileResult struct {
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	count  int
+	err    error
+	done   chan struct{}
+}
+
+// parseFiles parses each of the inputs using a pool of jobs workers. The
+// output of each input is written whole and in the order given, followed by
+// each input's error if it could not be parsed and a summary of the files,
+// transactions and failures.
+func (p *parser) parseFiles(inputs []string, jobs int) error {
+	jobs = min(jobs, len(inputs))
+
+	// Each worker reuses one decoder, created up front so that problems with
+	// the settings are reported before any input is read.
+	decoders := make([]*btd.Decoder, jobs)
+	for i := range decoders {
+		decoder, err := p.newDecoder()
+		if err != nil {
+			return err
+		}
+		decoders[i] = decoder
+	}
+
+	results := make([]*fileResult, len(inputs))
+	for i := range results {
+		results[i] = &fileResult{done: make(chan struct{})}
+	}
+
+	// Limit how far parsing runs ahead of the output so that the buffered
+	// output of many files is not held at once.
+	ahead := make(chan struct{}, 2*jobs)
+	work := make(chan int)
+
+	go func() {
+		for i := range inputs {
+			ahead <- struct{}{}
+			work <- i
+		}
+		close(work)
+	}()
+
+	// Status messages are buffered with the rendered transactions when both
+	// are written to standard output, keeping them interleaved.
+	statusStdout := statusOutput() == os.Stdout
+
+	var wg sync.WaitGroup
+
+	for _, decoder := range decoders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				p.parseFile(decoder, inputs[i], results[i], statusStdout)
+				close(results[i].done)
+			}
+		}()
+	}
+
+	transactions, failures := 0, 0
+
+	for i, result := range results {
+		<-result.done
+
+		os.Stdout.Write(result.stdout.Bytes())
+		os.Stderr.Write(result.stderr.Bytes())
+		if result.err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", result.err)
+			failures++
+		}
+
+		transactions += result.count
+		results[i] = nil
+		<-ahead
+	}
+
+	wg.Wait()
+
+	fmt.Fprintf(statusOutput(), "Parsed %d file(s): %d transaction(s), %d failure(s)\n", len(inputs), transactions, failures)
+
+	if failures > 0 {
+		return fmt.Errorf("failed to parse %d of %d file(s)", failures, len(inputs))
+	}
+
+	return nil
+}
+
+// parseFile parses the input at path into result, buffering its output and
+// the status messages with it if statusStdout is set.
+func (p *parser) parseFile(decoder *btd.Decoder, path string, result *fileResult, statusStdout bool) {
+	file, name, err := openInput(path)
+	if err != nil {
+		result.err = err
+		return
+	}
+	defer file.Close()
+
+	out := output{data: &result.stdout, status: &result.stderr, warnings: &result.stderr}
+	if statusStdout {
+		out.status = &result.stdout
+	}
+
+	result.count, result.err = p.parse(decoder, file, name, out)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+)
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert <input> [<output>]",
+	Short: "Convert a tag map file between formats",
+	Long: `Convert a tag map file between the dat, json, yaml, toml and csv formats. The
+input and output formats are detected from the file extensions unless given
+using the --from and --to flags. The output is written to standard output if no
+output path is given, in which case the --to flag is required.
+
+Groups cannot be written in the csv format.
+
+Examples:
+  btd-cli tagmap convert tagmap.dat tagmap.yaml
+  btd-cli tagmap convert --to json tagmap.dat`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+
+		inputFormat, err := btd.ParseTagMapFormat(from)
+		if err != nil {
+			return err
+		}
+
+		outputFormat, err := btd.ParseTagMapFormat(to)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == btd.FormatAuto {
+			if len(args) < 2 {
+				return errors.New("output format must be given using --to when writing to standard output")
+			}
+			outputFormat = btd.DetectTagMapFormat(args[1])
+		}
+
+		tagMap, err := (&btd.TagMapLoader{Format: inputFormat}).Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		// The tag map is rendered in full before the output file is created,
+		// so that a failed conversion does not leave a partial file behind.
+		var buf bytes.Buffer
+
+		if err := btd.WriteTagMap(&buf, tagMap, outputFormat); err != nil {
+			return fmt.Errorf("unable to write tag map: %w", err)
+		}
+
+		if len(args) < 2 {
+			_, err := buf.WriteTo(os.Stdout)
+			return err
+		}
+
+		return os.WriteFile(args[1], buf.Bytes(), 0o644)
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().String("from", btd.FormatAuto.String(), "format of the input file (auto, dat, json, yaml, toml or csv)")
+	convertCmd.Flags().String("to", btd.FormatAuto.String(), "format of the output (auto, dat, json, yaml, toml or csv)")
+}
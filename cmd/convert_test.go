@@ -0,0 +1,70 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInitAddsConvertCommand(t *testing.T) {
+	Convey("Given initialisation has completed", t, func() {
+
+		Convey("When checking the tagmap command's children", func() {
+			cmds := tagmapCmd.Commands()
+
+			Convey("Then the convert command should be present", func() {
+				So(cmds, ShouldContain, convertCmd)
+			})
+		})
+	})
+}
+
+func TestUnitConvertFailureLeavesNoOutputFile(t *testing.T) {
+	Convey("Given a tag map with groups, which cannot be written as CSV", t, func() {
+
+		dir := t.TempDir()
+
+		input := filepath.Join(dir, "tagmap.dat")
+		if err := os.WriteFile(input, []byte("0001 one\n0002 two\ngroup block 0001 0002\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		output := filepath.Join(dir, "tagmap.csv")
+
+		Convey("When converting the tag map to a CSV file", func() {
+			err := convertCmd.RunE(convertCmd, []string{input, output})
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then no output file should be created", func() {
+				_, statErr := os.Stat(output)
+				So(os.IsNotExist(statErr), ShouldBeTrue)
+			})
+		})
+	})
+}
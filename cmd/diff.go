@@ -0,0 +1,95 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Show the changes between two versions of a tag map",
+	Long: `Show the ids added to, removed from, renamed in or reassigned between two
+versions of a tag map file. An id is reported as renamed if its new name is not
+used elsewhere in either tag map, and as reassigned if its old or new name is
+mapped from another id.
+
+Removed, renamed and reassigned ids are breaking changes, as data parsed using
+the old tag map would be read differently using the new one. The command exits
+with a non-zero status if any breaking changes are found.
+
+Both tag maps are read in the format given by the tag-map-format setting, and
+refused if they contain conflicting mappings when the strict-tag-map setting
+is enabled.
+
+Examples:
+  btd-cli tagmap diff tagmap-1.0.dat tagmap-1.1.dat
+  btd-cli tagmap diff --output json tagmap-1.0.dat tagmap-1.1.dat`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		loader, err := newTagMapLoader()
+		if err != nil {
+			return err
+		}
+
+		oldMap, err := loader.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		newMap, err := loader.Load(args[1])
+		if err != nil {
+			return err
+		}
+
+		renderer, err := newRenderer()
+		if err != nil {
+			return err
+		}
+
+		changes := btd.DiffTagMaps(oldMap, newMap)
+		fmt.Println(renderer.RenderTagMapDiff(changes))
+
+		breaking := 0
+		for _, change := range changes {
+			if change.Kind.Breaking() {
+				breaking++
+			}
+		}
+
+		if breaking > 0 {
+			return fmt.Errorf("found %d breaking change(s) between tag maps: %s and %s", breaking, args[0], args[1])
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(diffCmd)
+}
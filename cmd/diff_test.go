@@ -0,0 +1,66 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInitAddsDiffCommand(t *testing.T) {
+	Convey("Given initialisation has completed", t, func() {
+
+		Convey("When checking the tagmap command's children", func() {
+			cmds := tagmapCmd.Commands()
+
+			Convey("Then the diff command should be present", func() {
+				So(cmds, ShouldContain, diffCmd)
+			})
+		})
+	})
+}
+
+func TestUnitDiffHonoursStrictTagMapSetting(t *testing.T) {
+	Convey("Given a tag map with conflicting mappings", t, func() {
+
+		path := filepath.Join(t.TempDir(), "tagmap.dat")
+		if err := os.WriteFile(path, []byte("0001 one\n0002 one\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When comparing it with the strict-tag-map setting enabled", func() {
+			viper.Set("strict-tag-map", true)
+			defer viper.Set("strict-tag-map", false)
+
+			err := diffCmd.RunE(diffCmd, []string{path, path})
+
+			Convey("Then the tag map should be refused", func() {
+				So(err.Error(), ShouldContainSubstring, "name one is already used for id 0001")
+			})
+		})
+	})
+}
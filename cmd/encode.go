@@ -0,0 +1,173 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// encodeCmd represents the encode command
+var encodeCmd = &cobra.Command{
+	Use:   "encode [<tag>=<value>...]",
+	Short: "Encode structured input into a business transaction data string",
+	Long: `Encode a list of tags and values into a business transaction data (BTD) string.
+Each tag may be given as either a numeric tag id or an XML tag name from the tag
+map; the id and length fields are zero-padded automatically.
+
+Tags and values may be supplied as '<tag>=<value>' arguments, or read from a JSON
+or YAML file using the --file flag (use '-' to read from standard input). The file
+must contain either a mapping of tags to values, or a sequence of single-entry
+mappings when the same tag needs to appear more than once. Tags are encoded in
+the order given.
+
+Examples:
+  btd-cli encode company_number=AB012345 '2007=Crown Way'
+  btd-cli encode --file transaction.yaml
+  echo '[{"2007": "Crown Way"}, {"2007": "Maindy"}]' | btd-cli encode -f -`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		tagMap, err := loadTagMap()
+		if err != nil {
+			return err
+		}
+
+		var fields []btd.Field
+
+		if path, _ := cmd.Flags().GetString("file"); len(path) > 0 {
+			fields, err = readFieldsFromFile(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, arg := range args {
+			tag, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("argument must be in the form <tag>=<value>: %s", arg)
+			}
+
+			fields = append(fields, btd.Field{Tag: tag, Value: value})
+		}
+
+		charset, unit, err := dataEncoding()
+		if err != nil {
+			return err
+		}
+
+		dialect, err := loadDialect()
+		if err != nil {
+			return err
+		}
+
+		encoder := btd.NewEncoder(os.Stdout, tagMap)
+		encoder.Charset = charset
+		encoder.LengthUnit = unit
+		encoder.Dialect = dialect
+
+		return encoder.Encode(fields)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(encodeCmd)
+
+	encodeCmd.Flags().StringP("file", "f", "", "path to a JSON or YAML input file ('-' for standard input)")
+}
+
+// readFieldsFromFile reads the tags and values to encode from a JSON or YAML
+// file, preserving the order in which they appear.
+func readFieldsFromFile(path string) ([]btd.Field, error) {
+	var r io.Reader = os.Stdin
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		r = file
+	}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("input file cannot be empty")
+		}
+		return nil, fmt.Errorf("unable to parse input file: %w", err)
+	}
+
+	root := doc.Content[0]
+
+	switch root.Kind {
+	case yaml.MappingNode:
+		return fieldsFromMapping(root)
+	case yaml.SequenceNode:
+		var fields []btd.Field
+
+		for _, item := range root.Content {
+			if item.Kind != yaml.MappingNode || len(item.Content) != 2 {
+				return nil, fmt.Errorf("line %d: sequence entries must be single-entry mappings", item.Line)
+			}
+
+			field, err := fieldsFromMapping(item)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, field...)
+		}
+
+		return fields, nil
+	}
+
+	return nil, fmt.Errorf("line %d: input must be a mapping or sequence", root.Line)
+}
+
+func fieldsFromMapping(node *yaml.Node) ([]btd.Field, error) {
+	var fields []btd.Field
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if value.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: value for tag %s must be a scalar", value.Line, key.Value)
+		}
+
+		if value.ShortTag() == "!!null" {
+			return nil, fmt.Errorf("line %d: value for tag %s cannot be null; use \"\" for an empty value", value.Line, key.Value)
+		}
+
+		fields = append(fields, btd.Field{Tag: key.Value, Value: value.Value})
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInitAddsEncodeCommand(t *testing.T) {
+	Convey("Given initialisation has completed", t, func() {
+
+		Convey("When checking the root command's children", func() {
+			cmds := rootCmd.Commands()
+
+			Convey("Then the encode command should be present", func() {
+				So(cmds, ShouldContain, encodeCmd)
+			})
+		})
+	})
+}
+
+func TestUnitReadFieldsFromFile(t *testing.T) {
+	Convey("Given an input file", t, func() {
+
+		path := filepath.Join(t.TempDir(), "transaction.yaml")
+
+		Convey("When the file contains string values", func() {
+			So(os.WriteFile(path, []byte("one: abcd\n\"2\": \"\"\n"), 0o644), ShouldBeNil)
+
+			fields, err := readFieldsFromFile(path)
+
+			Convey("Then the fields should be returned in order", func() {
+				So(err, ShouldBeNil)
+				So(fields, ShouldResemble, []btd.Field{{Tag: "one", Value: "abcd"}, {Tag: "2", Value: ""}})
+			})
+		})
+
+		for _, value := range []string{"null", "~", ""} {
+			Convey(fmt.Sprintf("When the file contains the null value %q", value), func() {
+				So(os.WriteFile(path, []byte("one: abcd\ntwo: "+value+"\n"), 0o644), ShouldBeNil)
+
+				fields, err := readFieldsFromFile(path)
+
+				Convey("Then an error should be returned", func() {
+					So(fields, ShouldBeNil)
+					So(err, ShouldEqual, errors.New(`line 2: value for tag two cannot be null; use "" for an empty value`))
+				})
+			})
+		}
+
+		Convey("When the file contains a JSON null", func() {
+			So(os.WriteFile(path, []byte(`[{"one": "abcd"}, {"two": null}]`), 0o644), ShouldBeNil)
+
+			_, err := readFieldsFromFile(path)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldEqual, errors.New(`line 1: value for tag two cannot be null; use "" for an empty value`))
+			})
+		})
+	})
+}
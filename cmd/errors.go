@@ -0,0 +1,113 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/companieshouse/btd-cli/pkg/btd"
+)
+
+// highlightContext is the number of bytes or characters of data shown either
+// side of the segment at fault when highlighting a parse error.
+const highlightContext = 40
+
+var (
+	segmentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Underline(true)
+	caretStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+)
+
+// highlightParseError returns err annotated with the transaction data it was
+// found in, with the segment at fault highlighted and marked with carets.
+// Offsets are counted in characters if characters is true, or bytes otherwise.
+// Errors other than a *btd.ParseError are returned unchanged.
+func highlightParseError(raw string, err error, characters bool) error {
+	var parseErr *btd.ParseError
+	if !errors.As(err, &parseErr) || len(raw) == 0 {
+		return err
+	}
+
+	units := splitUnits(raw, characters)
+
+	start, end := parseErr.Segment()
+	if start > len(units) || end > len(units) {
+		return err
+	}
+
+	from, to := max(0, start-highlightContext), min(len(units), end+highlightContext)
+
+	var before, after string
+	if from > 0 {
+		before = "…"
+	}
+	if to < len(units) {
+		after = "…"
+	}
+	before += strings.Join(units[from:start], "")
+	segment := strings.Join(units[start:end], "")
+	after = strings.Join(units[end:to], "") + after
+
+	data := before + segmentStyle.Render(segment) + after
+	carets := strings.Repeat(" ", lipgloss.Width(before)) +
+		caretStyle.Render(strings.Repeat("^", max(1, lipgloss.Width(segment)))) +
+		" " + describeSegment(parseErr, characters)
+
+	return fmt.Errorf("%w\n\n  %s\n  %s", err, data, carets)
+}
+
+// splitUnits splits data into the units that offsets are counted in: either
+// characters or individual bytes.
+func splitUnits(data string, characters bool) []string {
+	if characters {
+		return strings.Split(data, "")
+	}
+
+	units := make([]string, len(data))
+	for i := range len(data) {
+		units[i] = data[i : i+1]
+	}
+
+	return units
+}
+
+// describeSegment returns a short explanation of what is wrong with the
+// segment at fault.
+func describeSegment(err *btd.ParseError, characters bool) string {
+	unit := "bytes"
+	if characters {
+		unit = "characters"
+	}
+
+	switch {
+	case err.Truncated():
+		return fmt.Sprintf("expected %d %s, found %d", err.Expected, unit, err.Available)
+	case err.Kind == btd.UnknownID:
+		return "id not found in tag map"
+	case err.Kind == btd.MissingSeparator:
+		return "expected separator"
+	}
+
+	return fmt.Sprintf("expected %d digits", err.Expected)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitHighlightParseError(t *testing.T) {
+	Convey("Given a parse error for a truncated data field", t, func() {
+		raw := "00010001a00020010xyz"
+		parseErr := &btd.ParseError{
+			Kind: btd.TruncatedData, Offset: 9, FieldOffset: 17, ID: "0002", Expected: 10, Available: 3,
+			Err: errors.New("reached EOF before parsing data field for tag with id: 0002"),
+		}
+
+		Convey("When highlighting the error", func() {
+			err := highlightParseError(raw, parseErr, false)
+
+			Convey("Then the data and carets should mark the segment at fault", func() {
+				So(err.Error(), ShouldEqual, "reached EOF before parsing data field for tag with id: 0002\n\n"+
+					"  00010001a00020010xyz\n"+
+					"                   ^^^ expected 10 bytes, found 3")
+			})
+
+			Convey("Then the original error should still be retrievable", func() {
+				var target *btd.ParseError
+				So(errors.As(err, &target), ShouldBeTrue)
+				So(target, ShouldEqual, parseErr)
+			})
+		})
+	})
+
+	Convey("Given an error that is not a parse error", t, func() {
+		original := errors.New("some other problem")
+
+		Convey("When highlighting the error", func() {
+			err := highlightParseError("00010001a", original, false)
+
+			Convey("Then the error should be returned unchanged", func() {
+				So(err, ShouldEqual, original)
+			})
+		})
+	})
+}
@@ -22,73 +22,147 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"runtime"
 
-	"github.com/companieshouse/btd-cli/pkg/btd"
-	"github.com/companieshouse/btd-cli/pkg/btd/renderer/table"
+	"github.com/companieshouse/btd-cli/pkg/decompress"
+	"github.com/companieshouse/btd-cli/pkg/follow"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // fileCmd represents the file command
 var fileCmd = &cobra.Command{
-	Use:   "file <path>",
-	Short: "Parse business transaction data from an input file",
-	Long: `Parse the content of a file containing business transaction data (BTD) into a
-human-readable output format. Each line within the file is assumed to contain a
-complete business transaction data string.
-	
+	Use:   "file <path>...",
+	Short: "Parse business transaction data from input files",
+	Long: `Parse the content of files containing business transaction data (BTD) into a
+human-readable output format. Each line within a file is assumed to contain a
+complete business transaction data string. Use '-' as the path to read from
+standard input. Files compressed with gzip, bzip2, zstd or xz are decompressed
+as they are read.
+
+Several paths may be given, along with glob patterns matching files and, using
+the --recursive flag, directories whose files are all parsed. The files are
+parsed concurrently by the number of workers given by the --jobs flag, but the
+output of each file is displayed whole and in the order given, followed by a
+summary of the files, transactions and failures. A file that cannot be parsed
+does not stop the remaining files being parsed.
+
+Use the --follow flag to keep reading lines as they are appended to a file, in
+the same way as 'tail -f', rendering each new transaction as it arrives. The
+file is read again from the start if it is truncated or rotated, and cannot be
+compressed. Lines that cannot be parsed are reported without stopping. Press
+Ctrl+C to stop.
+
 Examples:
-  btd-cli parse file <path>`,
-	Args: cobra.ExactArgs(1),
+  btd-cli parse file <path>
+  btd-cli parse file 'extracts/*.txt'
+  btd-cli parse file --recursive --jobs 8 <directory>
+  btd-cli parse file --follow --extract 'data=(?P<btd>\S+)' app.log
+  grep AD01 <path> | btd-cli parse file -`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		tagMap, err := btd.LoadTagMap(os.ExpandEnv(viper.GetString("tag-map")))
-		if err != nil {
-			return err
-		}
-
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
-		fmt.Println("Using tag map:", tagMap.LoadedFromFile())
+		cmd.SilenceUsage = true
 
-		path := args[0]
+		if follow, _ := cmd.Flags().GetBool("follow"); follow {
+			if len(args) > 1 {
+				return errors.New("only one file can be followed")
+			}
+			return followInput(cmd, args[0])
+		}
 
-		if len(path) <= 0 {
-			return errors.New("filename cannot be empty")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		if jobs < 1 {
+			return errors.New("jobs must be at least 1")
 		}
 
-		file, err := os.Open(path)
+		recursive, _ := cmd.Flags().GetBool("recursive")
+
+		inputs, err := resolveInputs(args, recursive)
 		if err != nil {
 			return err
 		}
 
-		scanner := bufio.NewScanner(file)
-		scanner.Split(bufio.ScanLines)
-
-		line := 1
-
-		for scanner.Scan() {
-			if len(scanner.Text()) > 0 {
-				data, err := tagMap.ParseTagData(scanner.Text())
-				if err != nil {
-					return err
-				}
-
-				fmt.Printf("%v:%d:\n", path, line)
-				fmt.Println(table.New().Render(data))
+		// A single file is parsed as before, stopping at the first error.
+		if len(inputs) == 1 && len(args) == 1 && inputs[0] == args[0] {
+			file, name, err := openInput(inputs[0])
+			if err != nil {
+				return err
 			}
+			defer file.Close()
 
-			line++
+			return parseInput(cmd, file, name)
 		}
 
-		file.Close()
+		p, err := newParser(cmd)
+		if err != nil {
+			return err
+		}
 
-		return nil
+		return p.parseFiles(inputs, jobs)
 	},
 }
 
 func init() {
 	parseCmd.AddCommand(fileCmd)
+
+	fileCmd.Flags().BoolP("follow", "f", false, "keep reading lines appended to the file")
+	fileCmd.Flags().BoolP("recursive", "r", false, "parse the files within directories and their subdirectories")
+	fileCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of files to parse concurrently")
+}
+
+// followInput parses the file at path as it grows until interrupted.
+func followInput(cmd *cobra.Command, path string) error {
+	if path == stdinPath {
+		return errors.New("cannot follow standard input")
+	}
+
+	if format, err := detectCompression(path); err != nil {
+		return err
+	} else if format != decompress.None {
+		return fmt.Errorf("cannot follow %s compressed file: %s", format, path)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	file, err := follow.Open(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	file.Notify = func(event follow.Event) {
+		fmt.Fprintf(statusOutput(), "%s: file %s, reading from the start\n", path, event)
+	}
+
+	p, err := newParser(cmd)
+	if err != nil {
+		return err
+	}
+
+	decoder, err := p.newDecoder()
+	if err != nil {
+		return err
+	}
+
+	return p.follow(decoder, file, path, standardOutput())
+}
+
+// detectCompression returns the compression format of the file at path.
+func detectCompression(path string) (decompress.Format, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return decompress.None, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 6)
+	n, _ := io.ReadFull(file, header)
+
+	return decompress.Detect(header[:n]), nil
 }
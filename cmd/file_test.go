@@ -39,3 +39,16 @@ func TestUnitInitAddsFileCommand(t *testing.T) {
 		})
 	})
 }
+
+func TestUnitFollowInputRejectsStandardInput(t *testing.T) {
+	Convey("Given the path '-'", t, func() {
+
+		Convey("When following the input", func() {
+			err := followInput(fileCmd, "-")
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, "cannot follow standard input")
+			})
+		})
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+)
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find <name-or-regex>",
+	Short: "Find tags in the tag map by name",
+	Long: `Find the tags whose XML tag name or alias matches the given name or regular
+expression. A plain name matches any name containing it; anchor the expression
+with '^' and '$' to match a name exactly.
+
+Examples:
+  btd-cli tagmap find postcode
+  btd-cli tagmap find '^company_number$'
+  btd-cli tagmap find 'address_line_[0-9]'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		pattern, err := regexp.Compile(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid name or regular expression: %w", err)
+		}
+
+		tagMap, err := loadTagMap()
+		if err != nil {
+			return err
+		}
+
+		renderer, err := newRenderer()
+		if err != nil {
+			return err
+		}
+
+		printTagMapInUse(tagMap)
+
+		matches := findTags(tagMap.Tags(), pattern)
+		if len(matches) == 0 {
+			return fmt.Errorf("no tags found matching: %s", args[0])
+		}
+
+		fmt.Println(renderer.RenderTagMap(matches))
+
+		return nil
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(findCmd)
+}
+
+// findTags returns the tags whose name or any alias matches pattern.
+func findTags(tags []btd.TagDef, pattern *regexp.Regexp) []btd.TagDef {
+	var matches []btd.TagDef
+
+	for _, tag := range tags {
+		if pattern.MatchString(tag.Name) || slices.ContainsFunc(tag.Aliases, pattern.MatchString) {
+			matches = append(matches, tag)
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInitAddsFindCommand(t *testing.T) {
+	Convey("Given initialisation has completed", t, func() {
+
+		Convey("When checking the tagmap command's children", func() {
+			cmds := tagmapCmd.Commands()
+
+			Convey("Then the find command should be present", func() {
+				So(cmds, ShouldContain, findCmd)
+			})
+		})
+	})
+}
+
+func TestUnitFindTags(t *testing.T) {
+	Convey("Given tag definitions with names and aliases", t, func() {
+
+		tags := []btd.TagDef{
+			{ID: "0001", Name: "company_number", Aliases: []string{"crn"}},
+			{ID: "0002", Name: "postcode"},
+			{ID: "0003", Name: "registered_office_postcode"},
+		}
+
+		Convey("When finding tags using a plain name", func() {
+			matches := findTags(tags, regexp.MustCompile("postcode"))
+
+			Convey("Then every tag containing the name should be found", func() {
+				So(matches, ShouldResemble, tags[1:])
+			})
+		})
+
+		Convey("When finding tags using an anchored expression", func() {
+			matches := findTags(tags, regexp.MustCompile("^postcode$"))
+
+			Convey("Then only the exact match should be found", func() {
+				So(matches, ShouldResemble, tags[1:2])
+			})
+		})
+
+		Convey("When finding tags using an alias", func() {
+			matches := findTags(tags, regexp.MustCompile("^crn$"))
+
+			Convey("Then the aliased tag should be found", func() {
+				So(matches, ShouldResemble, tags[:1])
+			})
+		})
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+)
+
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:   "get <id>",
+	Short: "Look up a tag in the tag map by id",
+	Long: `Look up the XML tag name, type, aliases and description of the tag with the
+given id, along with its date layout or the meaning of each of its codes. Ids
+are matched numerically, so '0001' and '1' refer to the same tag.
+
+Examples:
+  btd-cli tagmap get 0001`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		tagMap, err := loadTagMap()
+		if err != nil {
+			return err
+		}
+
+		renderer, err := newRenderer()
+		if err != nil {
+			return err
+		}
+
+		printTagMapInUse(tagMap)
+
+		def, ok := tagMap.Lookup(args[0])
+		if !ok {
+			return fmt.Errorf("unknown id: %s", args[0])
+		}
+
+		fmt.Println(renderer.RenderTagMap([]btd.TagDef{def}))
+
+		return nil
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(getCmd)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/companieshouse/btd-cli/pkg/decompress"
+)
+
+// stdinPath is the path given to read from standard input, and stdinName the
+// name used for it in locations.
+const (
+	stdinPath = "-"
+	stdinName = "<stdin>"
+)
+
+// stdin is the reader used for standard input.
+var stdin io.Reader = os.Stdin
+
+// openInput opens the file at path for reading, or standard input if path is
+// "-", decompressing data compressed with gzip, bzip2, zstd or xz. It returns
+// the name used to describe the input's location.
+func openInput(path string) (io.ReadCloser, string, error) {
+	if len(path) <= 0 {
+		return nil, "", errors.New("filename cannot be empty")
+	}
+
+	if path == stdinPath {
+		r, err := decompressInput(io.NopCloser(stdin), stdinName)
+		return r, stdinName, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r, err := decompressInput(file, path)
+	return r, path, err
+}
+
+// decompressedInput is an input read through a decompressor, closing both
+// when closed.
+type decompressedInput struct {
+	io.ReadCloser
+	file io.Closer
+}
+
+func (d *decompressedInput) Close() error {
+	return errors.Join(d.ReadCloser.Close(), d.file.Close())
+}
+
+// decompressInput returns a reader of the input file named name,
+// decompressed if it is compressed. The file is closed if an error is
+// returned.
+func decompressInput(file io.ReadCloser, name string) (io.ReadCloser, error) {
+	r, _, err := decompress.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &decompressedInput{ReadCloser: r, file: file}, nil
+}
+
+// resolveInputs expands the paths given to a command into the inputs to read,
+// in order. Paths containing glob patterns are replaced by the files matching
+// them, and directories by the files they contain if recursive is set, walked
+// in lexical order and skipping hidden files and directories.
+func resolveInputs(paths []string, recursive bool) ([]string, error) {
+	var inputs []string
+
+	stdinGiven := false
+
+	for _, path := range paths {
+		if path == stdinPath {
+			if stdinGiven {
+				return nil, errors.New("standard input can only be given once")
+			}
+			stdinGiven = true
+			inputs = append(inputs, path)
+			continue
+		}
+
+		matches := []string{path}
+		if strings.ContainsAny(path, "*?[") {
+			var err error
+			if matches, err = glob(path); err != nil {
+				return nil, fmt.Errorf("invalid pattern: %s", path)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match pattern: %s", path)
+			}
+		}
+
+		for _, match := range matches {
+			files, err := expandDir(match, recursive)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, files...)
+		}
+	}
+
+	return inputs, nil
+}
+
+// glob returns the files matching pattern. As in the shell, hidden files only
+// match a pattern whose final element starts with a dot.
+func glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil || strings.HasPrefix(filepath.Base(pattern), ".") {
+		return matches, err
+	}
+
+	visible := matches[:0]
+	for _, match := range matches {
+		if !strings.HasPrefix(filepath.Base(match), ".") {
+			visible = append(visible, match)
+		}
+	}
+
+	return visible, nil
+}
+
+// expandDir returns the files within the directory at path if recursive is
+// set, or path itself if it is not a directory.
+func expandDir(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		// Files that cannot be read are reported when they are opened.
+		return []string{path}, nil
+	}
+
+	if !recursive {
+		return nil, fmt.Errorf("%s is a directory, use --recursive to read the files it contains", path)
+	}
+
+	var files []string
+
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if file != path && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.Type().IsRegular() {
+			files = append(files, file)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
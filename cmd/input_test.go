@@ -0,0 +1,182 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitOpenInput(t *testing.T) {
+	Convey("Given standard input containing data", t, func() {
+
+		original := stdin
+		stdin = strings.NewReader("00010001a\n")
+		defer func() { stdin = original }()
+
+		Convey("When opening the input '-'", func() {
+			r, name, err := openInput("-")
+			So(err, ShouldBeNil)
+			defer r.Close()
+
+			data, _ := io.ReadAll(r)
+
+			Convey("Then standard input should be read under the name <stdin>", func() {
+				So(name, ShouldEqual, "<stdin>")
+				So(string(data), ShouldEqual, "00010001a\n")
+			})
+		})
+	})
+
+	Convey("Given a file", t, func() {
+
+		path := filepath.Join(t.TempDir(), "data.txt")
+		if err := os.WriteFile(path, []byte("00010001a\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When opening the file", func() {
+			r, name, err := openInput(path)
+			So(err, ShouldBeNil)
+			defer r.Close()
+
+			Convey("Then the file should be read under its path", func() {
+				So(name, ShouldEqual, path)
+			})
+		})
+	})
+
+	Convey("Given a gzip compressed file", t, func() {
+
+		path := filepath.Join(t.TempDir(), "data.txt.gz")
+
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write([]byte("00010001a\n"))
+		w.Close()
+
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When opening the file", func() {
+			r, _, err := openInput(path)
+			So(err, ShouldBeNil)
+			defer r.Close()
+
+			data, _ := io.ReadAll(r)
+
+			Convey("Then the decompressed data should be read", func() {
+				So(string(data), ShouldEqual, "00010001a\n")
+			})
+		})
+	})
+
+	Convey("Given an empty path", t, func() {
+
+		Convey("When opening the input", func() {
+			_, _, err := openInput("")
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, "filename cannot be empty")
+			})
+		})
+	})
+}
+
+func TestUnitResolveInputs(t *testing.T) {
+	Convey("Given a directory of files", t, func() {
+
+		dir := t.TempDir()
+		for _, name := range []string{"b.txt", "a.txt", "sub/c.txt", ".hidden/d.txt", ".e.txt"} {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte("00010001a\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		Convey("When resolving paths in the order given", func() {
+			inputs, err := resolveInputs([]string{filepath.Join(dir, "b.txt"), "-", filepath.Join(dir, "a.txt")}, false)
+
+			Convey("Then the inputs should be kept in the same order", func() {
+				So(err, ShouldBeNil)
+				So(inputs, ShouldResemble, []string{filepath.Join(dir, "b.txt"), "-", filepath.Join(dir, "a.txt")})
+			})
+		})
+
+		Convey("When resolving a glob pattern", func() {
+			inputs, err := resolveInputs([]string{filepath.Join(dir, "*.txt")}, false)
+
+			Convey("Then the matching files should be returned in lexical order", func() {
+				So(err, ShouldBeNil)
+				So(inputs, ShouldResemble, []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")})
+			})
+		})
+
+		Convey("When resolving a glob pattern matching no files", func() {
+			_, err := resolveInputs([]string{filepath.Join(dir, "*.dat")}, false)
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, "no files match pattern: "+filepath.Join(dir, "*.dat"))
+			})
+		})
+
+		Convey("When resolving the directory recursively", func() {
+			inputs, err := resolveInputs([]string{dir}, true)
+
+			Convey("Then the files it contains should be returned, skipping hidden files", func() {
+				So(err, ShouldBeNil)
+				So(inputs, ShouldResemble, []string{
+					filepath.Join(dir, "a.txt"),
+					filepath.Join(dir, "b.txt"),
+					filepath.Join(dir, "sub", "c.txt"),
+				})
+			})
+		})
+
+		Convey("When resolving the directory without the recursive flag", func() {
+			_, err := resolveInputs([]string{dir}, false)
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, dir+" is a directory, use --recursive to read the files it contains")
+			})
+		})
+
+		Convey("When standard input is given twice", func() {
+			_, err := resolveInputs([]string{"-", "-"}, false)
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, "standard input can only be given once")
+			})
+		})
+	})
+}
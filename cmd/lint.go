@@ -0,0 +1,97 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [path...]",
+	Short: "Check tag map files for conflicting mappings",
+	Long: `Check tag map files for duplicated ids, duplicated XML tag names or aliases,
+ids that do not have the number of digits expected by the dialect, and
+differently written ids that refer to the same tag (e.g. '0010' and '10').
+Each problem is reported with the file and line it was found on.
+
+The tag maps given by the --tag-map flag (or tag-map configuration file
+setting) are checked unless paths are given. Layered tag maps are checked
+independently, as overriding the mappings of an earlier file is intended. The
+command exits with a non-zero status if any problems are found.
+
+Examples:
+  btd-cli tagmap lint
+  btd-cli tagmap lint <path>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		paths := args
+		if len(paths) == 0 {
+			if paths = tagMapPaths(); len(paths) == 0 {
+				return errNoTagMap
+			}
+		}
+
+		format, err := btd.ParseTagMapFormat(viper.GetString("tag-map-format"))
+		if err != nil {
+			return err
+		}
+
+		dialect, err := loadDialect()
+		if err != nil {
+			return err
+		}
+
+		problems := 0
+
+		for _, path := range paths {
+			tagMap, err := (&btd.TagMapLoader{Format: format}).Load(path)
+			if err != nil {
+				return err
+			}
+
+			issues := btd.Lint(tagMap.Tags(), path, dialect)
+			for _, issue := range issues {
+				fmt.Printf("%v [%s]\n", issue, issue.Kind)
+			}
+
+			problems += len(issues)
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("found %d problem(s) in tag map: %s", problems, strings.Join(paths, ", "))
+		}
+
+		fmt.Println("No problems found in tag map:", strings.Join(paths, ", "))
+		return nil
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every tag in the tag map",
+	Long: `List the id, XML tag name, type, aliases and description of every tag in the
+tag map, in the order they appear in the tag map file. Where tag maps are
+layered, mappings overridden by a later file are listed in place of the
+original mapping; use the --source flag to show which file each mapping was
+loaded from.
+
+Examples:
+  btd-cli tagmap list
+  btd-cli tagmap list --source -t tagmap.dat -t local.dat
+  btd-cli tagmap list --output json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		tagMap, err := loadTagMap()
+		if err != nil {
+			return err
+		}
+
+		renderer, err := newRenderer()
+		if err != nil {
+			return err
+		}
+
+		tags := tagMap.Tags()
+
+		if source, _ := cmd.Flags().GetBool("source"); !source {
+			tags = withoutSources(tags)
+		}
+
+		printTagMapInUse(tagMap)
+		fmt.Println(renderer.RenderTagMap(tags))
+
+		return nil
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(listCmd)
+
+	listCmd.Flags().Bool("source", false, "show the tag map file each mapping was loaded from")
+}
+
+// withoutSources returns a copy of tags with the file each was loaded from
+// omitted.
+func withoutSources(tags []btd.TagDef) []btd.TagDef {
+	out := make([]btd.TagDef, len(tags))
+
+	for i, tag := range tags {
+		tag.Source = ""
+		out[i] = tag
+	}
+
+	return out
+}
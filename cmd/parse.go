@@ -22,29 +22,257 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/follow"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // parseCmd represents the parse command
 var parseCmd = &cobra.Command{
 	Use:   "parse",
 	Short: "Parse business transaction data into a human-readable format",
-	Long: `Parse the content of a file or command-line argument string containing
-business transaction data (BTD) into a human-readable output format. Use the
-subcommands 'file' and 'string' to read the transaction data from a file or string
-argument respectively.
+	Long: `Parse the content of a file, standard input or command-line argument string
+containing business transaction data (BTD) into a human-readable output format.
+Use the subcommands 'file', 'stdin' and 'string' to read the transaction data
+from a file, standard input or string argument respectively.
 
-When parsing the content of a file, each line within the file is assumed to
+When parsing the content of a file or standard input, each line is assumed to
 contain a complete business transaction data string.
 
 String arguments must be quoted (single or double) when using the 'data'
 subcommand.
 
+Use the --lenient flag to display every tag that could be parsed, followed by a
+list of the problems found, rather than stopping at the first problem.
+
+Use the --types flag to identify the type of each transaction from a file of
+transaction type definitions. The detected type is shown above the tags, along
+with any required tags that are missing and any tags the type does not expect.
+
 Examples:
   btd-cli parse string '...'
-  btd-cli parse file <path>`,
+  btd-cli parse file <path>
+  btd-cli parse stdin < <path>`,
 }
 
 func init() {
 	rootCmd.AddCommand(parseCmd)
+
+	parseCmd.PersistentFlags().Bool("lenient", false, "report every problem found in a transaction instead of stopping at the first")
+	parseCmd.PersistentFlags().String("types", "", "path to transaction type definitions file")
+
+	viper.BindPFlag("transaction-types", parseCmd.PersistentFlags().Lookup("types"))
+}
+
+// loadTransactionTypes loads the transaction type definitions at the path
+// given by the transaction-types setting, expanding any environment variables
+// it contains. It returns nil if no definitions file is configured.
+func loadTransactionTypes(tagMap btd.TagMap) (*btd.TransactionTypes, error) {
+	path := os.ExpandEnv(viper.GetString("transaction-types"))
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	types, err := btd.LoadTransactionTypes(path, tagMap)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(statusOutput(), "Using transaction types:", types.Path())
+
+	return types, nil
+}
+
+// newDecoder returns a Decoder reading from r, configured from the parse
+// command's flags and the unknown-tags, charset, length-unit, dialect and log
+// extraction settings.
+func newDecoder(cmd *cobra.Command, r io.Reader, tagMap btd.TagMap) (*btd.Decoder, error) {
+	policy, err := btd.ParseUnknownTagPolicy(viper.GetString("unknown-tags"))
+	if err != nil {
+		return nil, err
+	}
+
+	charset, unit, err := dataEncoding()
+	if err != nil {
+		return nil, err
+	}
+
+	dialect, err := loadDialect()
+	if err != nil {
+		return nil, err
+	}
+
+	extractor, err := loadExtractor()
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := btd.NewDecoder(r, tagMap)
+	decoder.Lenient, _ = cmd.Flags().GetBool("lenient")
+	decoder.UnknownTags = policy
+	decoder.Charset = charset
+	decoder.LengthUnit = unit
+	decoder.Dialect = dialect
+	decoder.Extractor = extractor
+
+	return decoder, nil
+}
+
+// parser parses inputs using the tag map, renderer and transaction types
+// loaded once for the parse command.
+type parser struct {
+	cmd      *cobra.Command
+	tagMap   btd.TagMap
+	renderer renderer
+	types    *btd.TransactionTypes
+}
+
+// output holds the writers for the rendered transactions, informational
+// messages and warnings of a parsed input.
+type output struct {
+	data     io.Writer
+	status   io.Writer
+	warnings io.Writer
+}
+
+// standardOutput returns the output writing directly to standard output and
+// standard error.
+func standardOutput() output {
+	return output{data: os.Stdout, status: statusOutput(), warnings: os.Stderr}
+}
+
+// newParser loads the tag map, renderer and transaction types given by the
+// parse command's settings, reporting those in use.
+func newParser(cmd *cobra.Command) (*parser, error) {
+	tagMap, err := loadTagMap()
+	if err != nil {
+		return nil, err
+	}
+
+	printTagMapInUse(tagMap)
+
+	renderer, err := newRenderer()
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := loadTransactionTypes(tagMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parser{cmd: cmd, tagMap: tagMap, renderer: renderer, types: types}, nil
+}
+
+// newDecoder returns a Decoder configured for the parser, ready to be reset
+// to each input in turn.
+func (p *parser) newDecoder() (*btd.Decoder, error) {
+	decoder, err := newDecoder(p.cmd, nil, p.tagMap)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder.Types = p.types
+
+	return decoder, nil
+}
+
+// parse resets decoder to read r and renders each transaction to out,
+// introducing each by its location within the input named name. It returns
+// the number of transactions rendered.
+func (p *parser) parse(decoder *btd.Decoder, r io.Reader, name string, out output) (int, error) {
+	decoder.Reset(r)
+
+	count := 0
+
+	for {
+		tx, err := decoder.Decode()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, decodeError(decoder, name, err)
+		}
+
+		p.render(decoder, tx, name, out)
+		count++
+	}
+}
+
+// follow renders each transaction read from the followed file r to out as it
+// arrives, until r stops. Transactions that cannot be parsed are reported to
+// out's warnings and skipped, so that reading continues; other errors, such
+// as a failure to read the file, stop it.
+func (p *parser) follow(decoder *btd.Decoder, r *follow.Reader, name string, out output) error {
+	decoder.Reset(r)
+
+	for {
+		tx, err := decoder.Decode()
+		if err == io.EOF {
+			// The file is read again from the start once it has been
+			// truncated or rotated.
+			if !r.Next() {
+				return nil
+			}
+			decoder.Reset(r)
+			continue
+		}
+
+		var parseErr *btd.ParseError
+		if errors.As(err, &parseErr) {
+			fmt.Fprintln(out.warnings, "Error:", decodeError(decoder, name, err))
+			continue
+		}
+		if err != nil {
+			return decodeError(decoder, name, err)
+		}
+
+		p.render(decoder, tx, name, out)
+	}
+}
+
+// render writes tx to out, introduced by its location within the input
+// named name.
+func (p *parser) render(decoder *btd.Decoder, tx *btd.Transaction, name string, out output) {
+	printWarnings(out.warnings, fmt.Sprintf("%v:%d: ", name, decoder.Line()), tx)
+	fmt.Fprintf(out.status, "%v:%d:\n", name, decoder.Line())
+	fmt.Fprintln(out.data, p.renderer.RenderTransaction(tx))
+}
+
+// decodeError returns err, reported by decoder while reading the input named
+// name, prefixed with its location and highlighting the problem in the data.
+func decodeError(decoder *btd.Decoder, name string, err error) error {
+	return fmt.Errorf("%v:%d: %w", name, decoder.Line(), highlightParseError(decoder.Raw(), err, decoder.CharacterOffsets()))
+}
+
+// parseInput parses each transaction read from r, introducing each by its
+// location within the input named name.
+func parseInput(cmd *cobra.Command, r io.Reader, name string) error {
+	p, err := newParser(cmd)
+	if err != nil {
+		return err
+	}
+
+	decoder, err := p.newDecoder()
+	if err != nil {
+		return err
+	}
+
+	_, err = p.parse(decoder, r, name, standardOutput())
+
+	return err
+}
+
+// printWarnings writes any warnings reported for tx to w, prefixed with its
+// location if given.
+func printWarnings(w io.Writer, location string, tx *btd.Transaction) {
+	for _, warning := range tx.Warnings {
+		fmt.Fprintf(w, "Warning: %s%v\n", location, warning)
+	}
 }
@@ -22,7 +22,16 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/btd/renderer/json"
+	"github.com/companieshouse/btd-cli/pkg/follow"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -39,3 +48,49 @@ func TestUnitInitAddsParseCommand(t *testing.T) {
 		})
 	})
 }
+
+func TestUnitParserFollowContinuesAfterError(t *testing.T) {
+	Convey("Given a followed file containing a transaction that cannot be parsed", t, func() {
+
+		dir := t.TempDir()
+
+		tagMapPath := filepath.Join(dir, "tagmap.dat")
+		if err := os.WriteFile(tagMapPath, []byte("0001 one\n0002 two\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		tagMap, err := btd.LoadTagMap(tagMapPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(dir, "data.txt")
+		if err := os.WriteFile(path, []byte("0001XXXXa\n00020001b\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		r, err := follow.Open(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		r.Interval = 5 * time.Millisecond
+
+		p := &parser{tagMap: tagMap, renderer: json.New()}
+
+		Convey("When following the file", func() {
+			var data, status bytes.Buffer
+			err := p.follow(btd.NewDecoder(nil, tagMap), r, path, output{data: &data, status: &status, warnings: &status})
+
+			Convey("Then the error should be reported and reading continue", func() {
+				So(err, ShouldBeNil)
+				So(status.String(), ShouldContainSubstring, "Error: "+path+":1: found non-numeric length field")
+				So(status.String(), ShouldContainSubstring, path+":2:")
+				So(data.String(), ShouldContainSubstring, `"line": 2`)
+			})
+		})
+	})
+}
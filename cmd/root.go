@@ -22,9 +22,17 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/btd/renderer/json"
+	"github.com/companieshouse/btd-cli/pkg/btd/renderer/table"
+	"github.com/companieshouse/btd-cli/pkg/btd/rules"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -55,10 +63,42 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file path (default is $HOME/.btd-cli.toml)")
-	rootCmd.PersistentFlags().StringP("tag-map", "t", "", "path to tag map file")
+	rootCmd.PersistentFlags().StringSliceP("tag-map", "t", nil, "path to tag map file; repeat to layer tag maps, with later files overriding earlier ones")
+	rootCmd.PersistentFlags().String("tag-map-format", "", "format of the tag map file (auto, dat, json, yaml, toml or csv)")
+	rootCmd.PersistentFlags().Bool("strict-tag-map", false, "refuse to load a tag map with conflicting mappings")
+
+	rootCmd.PersistentFlags().String("charset", "", "character set of business transaction data (utf-8, iso-8859-1, windows-1252 or cp037)")
+	rootCmd.PersistentFlags().String("length-unit", "", "unit counted by length fields (bytes or characters)")
+	rootCmd.PersistentFlags().String("dialect", "", "name of the dialect describing the id and length fields")
+	rootCmd.PersistentFlags().String("unknown-tags", "", "handling of unknown tag ids (error, warn, placeholder or skip)")
+	rootCmd.PersistentFlags().String("extract", "", "regular expression extracting business transaction data from log lines, with a group named btd")
+	rootCmd.PersistentFlags().String("log-profile", "", "name of the log profile extracting business transaction data from log lines")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "output format (table or json)")
 
 	viper.BindPFlag("tag-map", rootCmd.PersistentFlags().Lookup("tag-map"))
-	viper.SetDefault("tag-map", "tagmap.dat")
+
+	viper.BindPFlag("tag-map-format", rootCmd.PersistentFlags().Lookup("tag-map-format"))
+	viper.SetDefault("tag-map-format", btd.FormatAuto.String())
+
+	viper.BindPFlag("strict-tag-map", rootCmd.PersistentFlags().Lookup("strict-tag-map"))
+
+	viper.BindPFlag("unknown-tags", rootCmd.PersistentFlags().Lookup("unknown-tags"))
+	viper.SetDefault("unknown-tags", "error")
+
+	viper.BindPFlag("charset", rootCmd.PersistentFlags().Lookup("charset"))
+	viper.SetDefault("charset", "utf-8")
+
+	viper.BindPFlag("length-unit", rootCmd.PersistentFlags().Lookup("length-unit"))
+	viper.SetDefault("length-unit", "bytes")
+
+	viper.BindPFlag("dialect", rootCmd.PersistentFlags().Lookup("dialect"))
+	viper.SetDefault("dialect", btd.Standard.Name)
+
+	viper.BindPFlag("extract", rootCmd.PersistentFlags().Lookup("extract"))
+	viper.BindPFlag("log-profile", rootCmd.PersistentFlags().Lookup("log-profile"))
+
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	viper.SetDefault("output", "table")
 }
 
 // initConfig reads in config file and environment variables if set.
@@ -82,3 +122,260 @@ func initConfig() {
 		}
 	}
 }
+
+// loadTagMap loads the tag maps found in the tag map search path, using the
+// loader returned by newTagMapLoader.
+func loadTagMap() (btd.TagMap, error) {
+	loader, err := newTagMapLoader()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := tagMapPaths()
+	if len(paths) == 0 {
+		return nil, errNoTagMap
+	}
+
+	return loader.Load(paths...)
+}
+
+// newTagMapLoader returns a TagMapLoader reading tag maps in the format given
+// by the tag-map-format setting. If the strict-tag-map setting is enabled, a
+// tag map with conflicting mappings is refused.
+func newTagMapLoader() (*btd.TagMapLoader, error) {
+	format, err := btd.ParseTagMapFormat(viper.GetString("tag-map-format"))
+	if err != nil {
+		return nil, err
+	}
+
+	loader := &btd.TagMapLoader{Strict: viper.GetBool("strict-tag-map"), Format: format}
+
+	if loader.Strict {
+		dialect, err := loadDialect()
+		if err != nil {
+			return nil, err
+		}
+		loader.Dialect = dialect
+	}
+
+	return loader, nil
+}
+
+// errNoTagMap is returned when no tag map is given or found in the tag map
+// search path.
+var errNoTagMap = errors.New("no tag map found: give one using the --tag-map flag, the " + tagMapEnv + " environment variable or the tag-map setting, or run 'btd-cli tagmap which' to see the locations searched")
+
+// tagMapEnv is the environment variable that may be used to give the paths
+// of the tag maps, separated by the OS path list separator.
+const tagMapEnv = "BTD_CLI_TAG_MAP"
+
+// tagMapCandidate is a location searched for tag maps.
+type tagMapCandidate struct {
+	source  string   // description of the location
+	paths   []string // paths found at the location; empty if not set or not found
+	missing string   // explanation of why no paths were found
+}
+
+// tagMapSearchPath returns the locations searched for tag maps, in order of
+// precedence: the --tag-map flag, tagmap.dat in the current directory,
+// tagmap.dat in $XDG_CONFIG_HOME/btd-cli, the BTD_CLI_TAG_MAP environment
+// variable, the tag-map config file setting and finally the embedded tag map.
+// Environment variables in paths given by the flag or config file setting are
+// expanded.
+func tagMapSearchPath() []tagMapCandidate {
+	flag := tagMapCandidate{source: "--tag-map flag", missing: "not set"}
+	if rootCmd.PersistentFlags().Changed("tag-map") {
+		flag.paths, _ = rootCmd.PersistentFlags().GetStringSlice("tag-map")
+	}
+
+	env := tagMapCandidate{source: tagMapEnv + " environment variable", missing: "not set"}
+	if value := os.Getenv(tagMapEnv); len(value) > 0 {
+		env.paths = filepath.SplitList(value)
+	}
+
+	config := tagMapCandidate{source: "tag-map setting in config file " + viper.ConfigFileUsed(), missing: "not set"}
+	if viper.InConfig("tag-map") {
+		if path, ok := viper.Get("tag-map").(string); ok {
+			config.paths = []string{path}
+		} else {
+			config.paths = viper.GetStringSlice("tag-map")
+		}
+	}
+
+	for _, candidate := range []*tagMapCandidate{&flag, &config} {
+		for i, path := range candidate.paths {
+			candidate.paths[i] = os.ExpandEnv(path)
+		}
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if len(configHome) == 0 {
+		if home, err := os.UserHomeDir(); err == nil {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+
+	return []tagMapCandidate{
+		flag,
+		findTagMap("current directory", defaultTagMapName),
+		findTagMap("$XDG_CONFIG_HOME/btd-cli", filepath.Join(configHome, "btd-cli", defaultTagMapName)),
+		env,
+		config,
+		{source: "built-in tag map", paths: []string{btd.EmbeddedTagMap}},
+	}
+}
+
+// defaultTagMapName is the name of the tag map file searched for.
+const defaultTagMapName = "tagmap.dat"
+
+// findTagMap returns a candidate for the tag map at path, if it exists.
+func findTagMap(source, path string) tagMapCandidate {
+	candidate := tagMapCandidate{source: source + " (" + path + ")", missing: "not found"}
+
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		candidate.paths = []string{path}
+	}
+
+	return candidate
+}
+
+// tagMapPaths returns the paths of the tag maps to be loaded, taken from the
+// first location in the search path at which any are given or found.
+func tagMapPaths() []string {
+	for _, candidate := range tagMapSearchPath() {
+		if len(candidate.paths) > 0 {
+			return candidate.paths
+		}
+	}
+
+	return nil
+}
+
+// renderer renders transactions, schema violations, tag maps and the
+// changes between them.
+type renderer interface {
+	btd.TransactionRenderer
+	btd.TagMapRenderer
+	btd.TagMapDiffRenderer
+	btd.ViolationRenderer
+	rules.Renderer
+}
+
+// newRenderer returns the renderer for the format given by the output
+// setting.
+func newRenderer() (renderer, error) {
+	switch format := viper.GetString("output"); format {
+	case "table":
+		return table.New(), nil
+	case "json":
+		return json.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// statusOutput returns the writer for informational messages, which are
+// written to standard error unless output is rendered as a table so that
+// machine-readable output is not interrupted.
+func statusOutput() io.Writer {
+	if viper.GetString("output") == "table" {
+		return os.Stdout
+	}
+
+	return os.Stderr
+}
+
+// printTagMapInUse reports the config file and tag map in use.
+func printTagMapInUse(tagMap btd.TagMap) {
+	fmt.Fprintln(statusOutput(), "Using config file:", viper.ConfigFileUsed())
+	fmt.Fprintln(statusOutput(), "Using tag map:", strings.Join(tagMap.LoadedFromFile(), ", "))
+}
+
+// dataEncoding returns the charset and length unit given by the charset and
+// length-unit settings.
+func dataEncoding() (*btd.Charset, btd.LengthUnit, error) {
+	charset, err := btd.LookupCharset(viper.GetString("charset"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unit, err := btd.ParseLengthUnit(viper.GetString("length-unit"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return charset, unit, nil
+}
+
+// loadDialect returns the dialect named by the dialect setting. Dialects
+// defined in the config file's dialects table take precedence over built-in
+// dialects, and inherit any settings they omit from the standard dialect.
+func loadDialect() (*btd.Dialect, error) {
+	name := viper.GetString("dialect")
+
+	settings := viper.Sub("dialects." + name)
+	if settings == nil {
+		return btd.LookupDialect(name)
+	}
+
+	dialect := *btd.Standard
+	dialect.Name = name
+
+	if settings.IsSet("id-width") {
+		dialect.IDWidth = settings.GetInt("id-width")
+	}
+	if settings.IsSet("length-width") {
+		dialect.LengthWidth = settings.GetInt("length-width")
+	}
+	if settings.IsSet("length-base") {
+		dialect.LengthBase = settings.GetInt("length-base")
+	}
+	if settings.IsSet("padding") {
+		padding := []rune(settings.GetString("padding"))
+		if len(padding) != 1 {
+			return nil, fmt.Errorf("dialect %s: padding must be a single character", name)
+		}
+		dialect.Padding = padding[0]
+	}
+	if settings.IsSet("separator") {
+		dialect.Separator = settings.GetString("separator")
+	}
+
+	if err := dialect.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &dialect, nil
+}
+
+// loadExtractor returns the Extractor for the pattern given by the extract
+// setting, or by the pattern of the log profile named by the log-profile
+// setting in the config file's log-profiles table. It returns nil if neither
+// is set.
+func loadExtractor() (*btd.Extractor, error) {
+	pattern := viper.GetString("extract")
+	name := viper.GetString("log-profile")
+
+	switch {
+	case len(pattern) > 0 && len(name) > 0:
+		return nil, errors.New("only one of a log pattern and a log profile may be given")
+	case len(name) > 0:
+		if !viper.IsSet("log-profiles." + name) {
+			return nil, fmt.Errorf("unknown log profile: %s", name)
+		}
+
+		pattern = viper.GetString("log-profiles." + name + ".pattern")
+		if len(pattern) == 0 {
+			return nil, fmt.Errorf("log profile %s: pattern cannot be empty", name)
+		}
+	case len(pattern) == 0:
+		return nil, nil
+	}
+
+	extractor, err := btd.NewExtractor(pattern)
+	if err != nil && len(name) > 0 {
+		return nil, fmt.Errorf("log profile %s: %w", name, err)
+	}
+
+	return extractor, err
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// stdinCmd represents the stdin command
+var stdinCmd = &cobra.Command{
+	Use:   "stdin",
+	Short: "Parse business transaction data from standard input",
+	Long: `Parse business transaction data (BTD) read from standard input into a
+human-readable output format. Each line of the input is assumed to contain a
+complete business transaction data string, so the output of other commands can
+be piped in without quoting. Equivalent to 'parse file -'.
+
+Examples:
+  grep AD01 <path> | btd-cli parse stdin
+  kubectl logs <pod> | btd-cli parse stdin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		r, name, err := openInput(stdinPath)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		return parseInput(cmd, r, name)
+	},
+}
+
+func init() {
+	parseCmd.AddCommand(stdinCmd)
+}
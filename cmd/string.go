@@ -24,12 +24,12 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/companieshouse/btd-cli/pkg/btd"
-	"github.com/companieshouse/btd-cli/pkg/btd/renderer/table"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 // stringCmd represents the string command
@@ -44,26 +44,36 @@ Examples:
   btd-cli parse string '...'`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		tagMap, err := btd.LoadTagMap(os.ExpandEnv(viper.GetString("tag-map")))
+		cmd.SilenceUsage = true
+
+		tagMap, err := loadTagMap()
 		if err != nil {
 			return err
 		}
 
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
-		fmt.Println("Using tag map:", tagMap.LoadedFromFile())
+		printTagMapInUse(tagMap)
 
-		path := args[0]
+		renderer, err := newRenderer()
+		if err != nil {
+			return err
+		}
+
+		decoder, err := newDecoder(cmd, strings.NewReader(args[0]), tagMap)
+		if err != nil {
+			return err
+		}
 
-		if len(path) <= 0 {
-			return errors.New("business transaction data string cannot be empty")
+		if decoder.Types, err = loadTransactionTypes(tagMap); err != nil {
+			return err
 		}
 
-		data, err := tagMap.ParseTagData(path)
+		tx, err := decodeString(decoder)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(table.New().Render(data))
+		printWarnings(os.Stderr, "", tx)
+		fmt.Println(renderer.RenderTransaction(tx))
 
 		return nil
 	},
@@ -72,3 +82,23 @@ Examples:
 func init() {
 	parseCmd.AddCommand(stringCmd)
 }
+
+var errMultipleTransactions = errors.New("data string contains more than one transaction")
+
+// decodeString decodes the single transaction read by decoder from a data
+// string argument.
+func decodeString(decoder *btd.Decoder) (*btd.Transaction, error) {
+	tx, err := decoder.Decode()
+	if err == io.EOF {
+		return nil, errors.New("business transaction data string cannot be empty")
+	}
+	if err != nil {
+		return nil, highlightParseError(decoder.Raw(), err, decoder.CharacterOffsets())
+	}
+
+	if decoder.More() {
+		return nil, errMultipleTransactions
+	}
+
+	return tx, nil
+}
@@ -22,8 +22,13 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/companieshouse/btd-cli/pkg/btd"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -39,3 +44,43 @@ func TestUnitInitAddsStringCommand(t *testing.T) {
 		})
 	})
 }
+
+func TestUnitDecodeString(t *testing.T) {
+	Convey("Given a tag map", t, func() {
+
+		path := filepath.Join(t.TempDir(), "tagmap.dat")
+		if err := os.WriteFile(path, []byte("0001 one\n0002 two\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		tagMap, err := btd.LoadTagMap(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When decoding a string containing one transaction", func() {
+			tx, err := decodeString(btd.NewDecoder(strings.NewReader("00010001a"), tagMap))
+
+			Convey("Then the transaction should be returned", func() {
+				So(err, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, btd.TagData{{"0001", "one", "0001", "a"}})
+			})
+		})
+
+		Convey("When decoding a string containing more than one transaction", func() {
+			_, err := decodeString(btd.NewDecoder(strings.NewReader("00010001a\n00020001b"), tagMap))
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, "data string contains more than one transaction")
+			})
+		})
+
+		Convey("When decoding an empty string", func() {
+			_, err := decodeString(btd.NewDecoder(strings.NewReader(""), tagMap))
+
+			Convey("Then an error should be returned", func() {
+				So(err.Error(), ShouldEqual, "business transaction data string cannot be empty")
+			})
+		})
+	})
+}
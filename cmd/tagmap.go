@@ -0,0 +1,56 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// tagmapCmd represents the tagmap command
+var tagmapCmd = &cobra.Command{
+	Use:   "tagmap",
+	Short: "Inspect, compare and convert tag map files",
+	Long: `Inspect, check, compare and convert the tag map files used to resolve the XML
+tag names of business transaction data. The following subcommands are
+available:
+
+  list     list every tag in the tag map
+  get      look up a tag by id
+  find     find tags by name, alias or regular expression
+  which    show which tag map is used and why
+  lint     check tag map files for conflicting mappings
+  diff     show the changes between two versions of a tag map
+  convert  convert a tag map file between formats
+
+Examples:
+  btd-cli tagmap list
+  btd-cli tagmap get 0001
+  btd-cli tagmap find postcode
+  btd-cli tagmap which
+  btd-cli tagmap lint <path>
+  btd-cli tagmap diff <old> <new>
+  btd-cli tagmap convert tagmap.dat tagmap.yaml`,
+}
+
+func init() {
+	rootCmd.AddCommand(tagmapCmd)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/btd/rules"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate business transaction data against a schema or rules",
+	Long: `Check the values of business transaction data (BTD) against the type, length,
+pattern, permitted values and required tags given in a schema file, and against
+rules spanning several tags given in a rules file. Use the subcommands 'file' and
+'string' to read the transaction data from a file or string argument
+respectively.
+
+The schema file is given using the --schema flag (or schema configuration file
+setting) and the rules file using the --rules flag (or rules configuration file
+setting); at least one must be given. The command exits with a non-zero status
+if any violations, or failures of rules with error severity, are found.
+
+Examples:
+  btd-cli validate string --schema schema.yaml '...'
+  btd-cli validate file --schema schema.yaml <path>
+  btd-cli validate file --rules rules.yaml <path>`,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.PersistentFlags().String("schema", "", "path to schema file")
+	validateCmd.PersistentFlags().String("rules", "", "path to rules file")
+
+	viper.BindPFlag("schema", validateCmd.PersistentFlags().Lookup("schema"))
+	viper.BindPFlag("rules", validateCmd.PersistentFlags().Lookup("rules"))
+}
+
+// loadSchema loads the schema at the path given by the schema setting,
+// expanding any environment variables it contains. It returns nil if no
+// schema file is configured.
+func loadSchema() (*btd.Schema, error) {
+	path := os.ExpandEnv(viper.GetString("schema"))
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	return btd.LoadSchema(path)
+}
+
+// loadRules loads the rules at the path given by the rules setting,
+// expanding any environment variables it contains. It returns nil if no
+// rules file is configured.
+func loadRules(tagMap btd.TagMap) (*rules.RuleSet, error) {
+	path := os.ExpandEnv(viper.GetString("rules"))
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	return rules.LoadRules(path, tagMap)
+}
+
+// validateInput checks each transaction read from r against the schema and
+// rules, printing the violations and rule failures found. If name is given,
+// each transaction is introduced by its location within the input.
+func validateInput(cmd *cobra.Command, r io.Reader, name string) error {
+	schema, err := loadSchema()
+	if err != nil {
+		return err
+	}
+
+	tagMap, err := loadTagMap()
+	if err != nil {
+		return err
+	}
+
+	ruleSet, err := loadRules(tagMap)
+	if err != nil {
+		return err
+	}
+
+	if schema == nil && ruleSet == nil {
+		return errors.New("schema or rules file must be given using the --schema or --rules flag, or the schema or rules setting")
+	}
+
+	printTagMapInUse(tagMap)
+	if schema != nil {
+		fmt.Fprintln(statusOutput(), "Using schema:", schema.Path())
+	}
+	if ruleSet != nil {
+		fmt.Fprintln(statusOutput(), "Using rules:", ruleSet.Path())
+	}
+
+	renderer, err := newRenderer()
+	if err != nil {
+		return err
+	}
+
+	decoder, err := newDecoder(cmd, r, tagMap)
+	if err != nil {
+		return err
+	}
+
+	violations, invalid := 0, 0
+
+	for {
+		tx, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+
+		location := ""
+		if len(name) > 0 {
+			location = fmt.Sprintf("%v:%d:", name, decoder.Line())
+		}
+
+		if err != nil {
+			err = highlightParseError(decoder.Raw(), err, decoder.CharacterOffsets())
+			if len(location) > 0 {
+				return fmt.Errorf("%s %w", location, err)
+			}
+			return err
+		}
+
+		// Input without a name is a data string argument, which must hold
+		// a single transaction.
+		if len(name) == 0 && decoder.More() {
+			return errMultipleTransactions
+		}
+
+		if len(location) > 0 {
+			fmt.Fprintln(statusOutput(), location)
+		}
+
+		var (
+			found    []*btd.Violation
+			failures []*rules.Failure
+		)
+
+		if schema != nil {
+			found = schema.Validate(tx)
+		}
+		if ruleSet != nil {
+			failures = ruleSet.Evaluate(tx.TagData())
+		}
+
+		if len(found) == 0 && len(failures) == 0 {
+			fmt.Fprintln(statusOutput(), "No violations found")
+			continue
+		}
+
+		if len(found) > 0 {
+			fmt.Println(renderer.RenderViolations(found))
+		}
+		if len(failures) > 0 {
+			fmt.Println(renderer.RenderRuleFailures(failures))
+		}
+
+		// Only rules with error severity cause validation to fail.
+		errs := len(found)
+		for _, failure := range failures {
+			if failure.Severity == rules.Error {
+				errs++
+			}
+		}
+
+		if errs > 0 {
+			violations += errs
+			invalid++
+		}
+	}
+
+	if violations > 0 {
+		return fmt.Errorf("found %d violation(s) in %d transaction(s)", violations, invalid)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// validateFileCmd represents the validate file command
+var validateFileCmd = &cobra.Command{
+	Use:   "file <path>",
+	Short: "Validate business transaction data from an input file",
+	Long: `Validate the content of a file containing business transaction data (BTD)
+against a schema. Each line within the file is assumed to contain a complete
+business transaction data string. Use '-' as the path to read from standard
+input.
+
+Examples:
+  btd-cli validate file --schema schema.yaml <path>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		file, name, err := openInput(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return validateInput(cmd, file, name)
+	},
+}
+
+func init() {
+	validateCmd.AddCommand(validateFileCmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInitAddsValidateStringCommand(t *testing.T) {
+	Convey("Given initialisation has completed", t, func() {
+
+		Convey("When checking the validate command's children", func() {
+			cmds := validateCmd.Commands()
+
+			Convey("Then the string command should be present", func() {
+				So(cmds, ShouldContain, validateStringCmd)
+			})
+		})
+	})
+}
+
+func TestUnitValidateStringRejectsMultipleTransactions(t *testing.T) {
+	Convey("Given a tag map and a schema", t, func() {
+
+		dir := t.TempDir()
+		tagMap := filepath.Join(dir, "local.dat")
+		schema := filepath.Join(dir, "schema.yaml")
+		if err := os.WriteFile(tagMap, []byte("0001 one\n0002 two\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(schema, []byte("tags:\n  one:\n    required: true\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Chdir(dir)
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		t.Setenv(tagMapEnv, tagMap)
+		viper.Set("schema", schema)
+		defer viper.Set("schema", "")
+
+		Convey("When validating a string containing more than one transaction", func() {
+			err := validateStringCmd.RunE(validateStringCmd, []string{"00010001a\n00020001b"})
+
+			Convey("Then the string should be rejected", func() {
+				So(err, ShouldEqual, errMultipleTransactions)
+			})
+		})
+	})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which",
+	Short: "Show which tag map is used and why",
+	Long: `Show the tag maps that will be used, and each location searched for them in
+order of precedence:
+
+  1. the --tag-map flag
+  2. tagmap.dat in the current directory
+  3. tagmap.dat in $XDG_CONFIG_HOME/btd-cli (or ~/.config/btd-cli)
+  4. the BTD_CLI_TAG_MAP environment variable
+  5. the tag-map setting in the config file
+  6. the minimal built-in tag map, a subset of the chtuxgw tag map
+
+The first location at which a tag map is given or found is used.
+
+Examples:
+  btd-cli tagmap which`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		used := false
+
+		for i, candidate := range tagMapSearchPath() {
+			var status string
+
+			switch {
+			case len(candidate.paths) == 0:
+				status = candidate.missing
+			case used:
+				status = "ignored: " + strings.Join(candidate.paths, ", ")
+			default:
+				status = "using: " + strings.Join(candidate.paths, ", ")
+				used = true
+			}
+
+			fmt.Printf("%d. %s: %s\n", i+1, candidate.source, status)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	tagmapCmd.AddCommand(whichCmd)
+}
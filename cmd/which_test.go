@@ -0,0 +1,97 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitInitAddsWhichCommand(t *testing.T) {
+	Convey("Given initialisation has completed", t, func() {
+
+		Convey("When checking the tagmap command's children", func() {
+			cmds := tagmapCmd.Commands()
+
+			Convey("Then the which command should be present", func() {
+				So(cmds, ShouldContain, whichCmd)
+			})
+		})
+	})
+}
+
+func TestUnitTagMapSearchPath(t *testing.T) {
+	Convey("Given no tag map flag or config file setting", t, func() {
+
+		dir := t.TempDir()
+		t.Chdir(dir)
+		t.Setenv(tagMapEnv, "")
+		t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+
+		Convey("When no tag map files exist", func() {
+			paths := tagMapPaths()
+
+			Convey("Then the built-in tag map should be used", func() {
+				So(paths, ShouldResemble, []string{btd.EmbeddedTagMap})
+			})
+		})
+
+		Convey("When the environment variable is set", func() {
+			t.Setenv(tagMapEnv, "base.dat"+string(filepath.ListSeparator)+"local.dat")
+
+			Convey("Then every tag map given by the environment variable should be used", func() {
+				So(tagMapPaths(), ShouldResemble, []string{"base.dat", "local.dat"})
+			})
+		})
+
+		Convey("When a tag map exists in the XDG config directory", func() {
+			path := filepath.Join(dir, "config", "btd-cli", "tagmap.dat")
+			So(os.MkdirAll(filepath.Dir(path), 0o755), ShouldBeNil)
+			So(os.WriteFile(path, []byte("0001 one\n"), 0o644), ShouldBeNil)
+
+			Convey("Then it should be used", func() {
+				So(tagMapPaths(), ShouldResemble, []string{path})
+			})
+
+			Convey("And a tag map exists in the current directory", func() {
+				So(os.WriteFile("tagmap.dat", []byte("0001 one\n"), 0o644), ShouldBeNil)
+
+				Convey("Then the tag map in the current directory should be used", func() {
+					So(tagMapPaths(), ShouldResemble, []string{"tagmap.dat"})
+				})
+
+				Convey("And the environment variable is set", func() {
+					t.Setenv(tagMapEnv, "base.dat")
+
+					Convey("Then the tag map in the current directory should still be used", func() {
+						So(tagMapPaths(), ShouldResemble, []string{"tagmap.dat"})
+					})
+				})
+			})
+		})
+	})
+}
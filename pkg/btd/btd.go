@@ -22,51 +22,65 @@ THE SOFTWARE.
 package btd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io"
-	"os"
-	"regexp"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type TagMap interface {
 	ParseTagData(data string) (TagData, error)
+	ParseTransaction(data string) (*Transaction, error)
 	GetTagName(id string) (string, error)
-	LoadTagMap(path string) (*TagMap, error)
-	LoadedFromFile() string
+	GetTagID(name string) (string, error)
+	Lookup(id string) (TagDef, bool)
+	Tags() []TagDef
+	Groups() []GroupDef
+	LoadedFromFile() []string
 }
 
 type tagMapData struct {
-	mappings map[string]string
-	path     string
+	tags   []TagDef
+	byID   map[string]*TagDef // keyed by normalised id
+	byName map[string]*TagDef // keyed by name and alias
+	groups []GroupDef
+	paths  []string
 }
 
 func (t *tagMapData) ParseTagData(data string) (TagData, error) {
+	tx, err := t.ParseTransaction(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.TagData(), nil
+}
+
+func (t *tagMapData) ParseTransaction(data string) (*Transaction, error) {
 
 	if len(data) == 0 {
 		return nil, errors.New("data string cannot be empty")
 	}
 
-	var tagData TagData
-	r := strings.NewReader(data)
-
-	for {
-		tag, err := parseTag(r, t)
-		if err != nil {
-			return nil, err
-		}
+	d := NewDecoder(strings.NewReader(data), t)
 
-		tagData = append(tagData, tag)
+	tx, err := d.Decode()
+	if err == io.EOF {
+		return nil, errors.New("data string cannot be empty")
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		if r.Len() == 0 {
-			break
-		}
+	if d.More() {
+		return nil, errors.New("data string contains more than one transaction")
 	}
 
-	return tagData, nil
+	return tx, nil
 }
 
 func (t *tagMapData) GetTagName(id string) (string, error) {
@@ -74,11 +88,23 @@ func (t *tagMapData) GetTagName(id string) (string, error) {
 		return "", errors.New("id cannot be empty")
 	}
 
-	name, ok := t.mappings[id]
+	def, ok := t.byID[normaliseID(id)]
 	if !ok {
 		return "", fmt.Errorf("unknown id: %s", id)
 	}
-	return name, nil
+	return def.Name, nil
+}
+
+func (t *tagMapData) GetTagID(name string) (string, error) {
+	if len(name) == 0 {
+		return "", errors.New("name cannot be empty")
+	}
+
+	def, ok := t.byName[name]
+	if !ok {
+		return "", fmt.Errorf("unknown tag name: %s", name)
+	}
+	return def.ID, nil
 }
 
 type TagData [][]string
@@ -86,7 +112,7 @@ type TagData [][]string
 func (t *TagData) GetMaxDataLength() int {
 	max_data_length := 0
 	for _, value := range *t {
-		if data_length := len(value[3]); data_length > max_data_length {
+		if data_length := utf8.RuneCountInString(value[3]); data_length > max_data_length {
 			max_data_length = data_length
 		}
 	}
@@ -94,90 +120,172 @@ func (t *TagData) GetMaxDataLength() int {
 	return max_data_length
 }
 
-func LoadTagMap(path string) (*tagMapData, error) {
-	tagMap := &tagMapData{make(map[string]string), path}
+// LoadTagMap loads the tag maps at paths, with each file adding to or
+// overriding the mappings of the files before it. The last mapping of any id
+// duplicated within a file is used.
+func LoadTagMap(paths ...string) (*tagMapData, error) {
+	return (&TagMapLoader{}).Load(paths...)
+}
 
-	if len(path) == 0 {
-		return nil, errors.New("path cannot be empty")
+// newTagMap merges the contents of tag map files loaded from paths. Mappings
+// and groups in each file replace those with the same id or name in earlier
+// files. Where an id is mapped more than once within a file the last mapping
+// is used; where a name or alias is used more than once the first is used.
+func newTagMap(files []*tagMapFile, paths []string) *tagMapData {
+	tagMap := &tagMapData{
+		byID:   make(map[string]*TagDef),
+		byName: make(map[string]*TagDef),
+		paths:  paths,
 	}
 
-	fp, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("unable to read tag map file: %s", path)
-	}
-	defer fp.Close()
+	layered := make(map[string]int) // position of each id mapped by an earlier file
 
-	s := bufio.NewScanner(fp)
-	s.Split(bufio.ScanLines)
+	for _, file := range files {
+		mapped := make(map[string]int)
 
-	pattern := regexp.MustCompile(`\s*([0-9]+)\s+([A-Za-z_]+)`)
+		for _, def := range file.tags {
+			key := normaliseID(def.ID)
 
-	for s.Scan() {
-		matches := pattern.FindStringSubmatch(s.Text())
+			if i, ok := layered[key]; ok {
+				tagMap.tags[i] = def
+				continue
+			}
 
-		if len(matches) == 3 {
-			id, tag := matches[1], matches[2]
-			tagMap.mappings[id] = tag
+			mapped[key] = len(tagMap.tags)
+			tagMap.tags = append(tagMap.tags, def)
+		}
+
+		maps.Copy(layered, mapped)
+
+		for _, def := range file.groups {
+			i := slices.IndexFunc(tagMap.groups, func(g GroupDef) bool { return g.Name == def.Name })
+			if i < 0 {
+				tagMap.groups = append(tagMap.groups, def)
+			} else {
+				tagMap.groups[i] = def
+			}
 		}
 	}
 
-	if len(tagMap.mappings) == 0 {
-		return nil, fmt.Errorf("no mappings in tag map file: %s", path)
+	for i := range tagMap.tags {
+		def := &tagMap.tags[i]
+		tagMap.byID[normaliseID(def.ID)] = def
+
+		for _, name := range append([]string{def.Name}, def.Aliases...) {
+			if _, ok := tagMap.byName[name]; !ok {
+				tagMap.byName[name] = def
+			}
+		}
 	}
 
-	return tagMap, nil
+	return tagMap
 }
 
-func (t *tagMapData) LoadedFromFile() string {
-	return t.path
+// Lookup returns the definition of the tag with the given id.
+func (t *tagMapData) Lookup(id string) (TagDef, bool) {
+	def, ok := t.byID[normaliseID(id)]
+	if !ok {
+		return TagDef{}, false
+	}
+	return *def, true
 }
 
-func parseTag(r *strings.Reader, t *tagMapData) ([]string, error) {
-	id, err := parseData(r, 4)
-	if err != nil {
-		return nil, errors.New("reached EOF before parsing ID field")
+func (t *tagMapData) Tags() []TagDef {
+	return t.tags
+}
+
+func (t *tagMapData) Groups() []GroupDef {
+	return t.groups
+}
+
+// LoadedFromFile returns the paths of the tag map files loaded, in the order
+// they were layered.
+func (t *tagMapData) LoadedFromFile() []string {
+	return t.paths
+}
+
+func parseTag(d *Decoder) (Tag, error) {
+	dialect := d.dialect()
+
+	if len(dialect.Separator) > 0 && d.offset > 0 {
+		sepOffset := d.offset
+		sepLength := measure(dialect.Separator, d.Charset, d.LengthUnit)
+
+		if sep, _ := d.readField(sepLength); sep != dialect.Separator {
+			return Tag{}, &ParseError{
+				Kind: MissingSeparator, Offset: sepOffset, FieldOffset: sepOffset, Expected: sepLength, Available: d.measure(sep),
+				Err: fmt.Errorf("expected separator %q at offset %d: found %q", dialect.Separator, sepOffset, sep),
+			}
+		}
 	}
 
-	if _, err := parseUIntValue(id); err != nil {
-		return nil, fmt.Errorf("found non-numeric id field: %s", id)
+	offset := d.offset
+
+	id, err := d.readField(dialect.IDWidth)
+	if err != nil {
+		return Tag{}, &ParseError{
+			Kind: TruncatedID, Offset: offset, FieldOffset: offset, ID: id, Expected: dialect.IDWidth, Available: d.measure(id),
+			Err: errors.New("reached EOF before parsing ID field"),
+		}
 	}
 
-	length, err := parseData(r, 4)
+	idValue, err := dialect.parseID(id)
 	if err != nil {
-		return nil, fmt.Errorf("reached EOF before parsing length field for tag with id: %s", id)
+		return Tag{}, &ParseError{
+			Kind: NonNumericID, Offset: offset, FieldOffset: offset, ID: id, Expected: dialect.IDWidth, Available: dialect.IDWidth,
+			Err: fmt.Errorf("found non-numeric id field: %s", id),
+		}
 	}
 
-	lengthUnit, err := parseUIntValue(length)
+	lengthOffset := d.offset
+
+	length, err := d.readField(dialect.LengthWidth)
 	if err != nil {
-		return nil, fmt.Errorf("found non-numeric length field for tag with id %s: %s", id, length)
+		return Tag{}, &ParseError{
+			Kind: TruncatedLength, Offset: offset, FieldOffset: lengthOffset, ID: id, Expected: dialect.LengthWidth, Available: d.measure(length),
+			Err: fmt.Errorf("reached EOF before parsing length field for tag with id: %s", id),
+		}
 	}
 
-	data, err := parseData(r, uint(lengthUnit))
+	lengthValue, err := dialect.parseLength(length)
 	if err != nil {
-		return nil, fmt.Errorf("reached EOF before parsing data field for tag with id: %s", id)
+		return Tag{}, &ParseError{
+			Kind: NonNumericLength, Offset: offset, FieldOffset: lengthOffset, ID: id, Expected: dialect.LengthWidth, Available: dialect.LengthWidth,
+			Err: fmt.Errorf("found non-numeric length field for tag with id %s: %s", id, length),
+		}
 	}
 
-	tag, err := t.GetTagName(id)
+	dataOffset := d.offset
+
+	data, err := d.readField(int(lengthValue))
 	if err != nil {
-		return nil, err
+		return Tag{}, &ParseError{
+			Kind: TruncatedData, Offset: offset, FieldOffset: dataOffset, ID: id, Expected: int(lengthValue), Available: d.measure(data),
+			Err: fmt.Errorf("reached EOF before parsing data field for tag with id: %s", id),
+		}
 	}
 
-	return []string{
-		id,
-		tag,
-		length,
-		data,
-	}, nil
-}
+	tag := Tag{
+		ID:             id,
+		DeclaredLength: int(lengthValue),
+		Value:          data,
+		Offset:         offset,
+		Dialect:        dialect,
+		lengthField:    length,
+	}
 
-func parseData(r *strings.Reader, length uint) (string, error) {
-	data := make([]byte, length)
-	n, err := r.Read(data)
-	if err == io.EOF || n < int(length) {
-		return "", fmt.Errorf("not enough data remaining to read bytes: %d", length)
+	def, ok := d.tagMap.Lookup(strconv.FormatUint(idValue, 10))
+	if !ok {
+		return tag, &ParseError{
+			Kind: UnknownID, Offset: offset, FieldOffset: offset, ID: id, Expected: dialect.IDWidth, Available: dialect.IDWidth,
+			Err: fmt.Errorf("unknown id: %s", id),
+		}
 	}
 
-	return string(data[:n]), nil
+	tag.Name = def.Name
+	tag.Decoded, tag.DecodeErr = def.DecodeValue(data)
+
+	return tag, nil
 }
 
 func parseUIntValue(data string) (uint64, error) {
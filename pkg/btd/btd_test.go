@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -171,6 +173,74 @@ func TestUnitGetTagNameWithValidID(t *testing.T) {
 	})
 }
 
+func TestUnitGetTagNameWithUnpaddedID(t *testing.T) {
+	Convey("Given a valid tag map and an ID without zero-padding", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When retrieving the tag name", func() {
+			name, err := tagMap.GetTagName("10")
+
+			Convey("The tag name should match the zero-padded ID", func() {
+				So(name, ShouldEqual, "ten")
+			})
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestUnitGetTagIDWithUnknownName(t *testing.T) {
+	Convey("Given a valid tag map and unknown tag name", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := "eleven"
+
+		Convey("When retrieving the tag id", func() {
+			id, err := tagMap.GetTagID(name)
+
+			Convey("The id should be empty", func() {
+				So(id, ShouldBeEmpty)
+			})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, fmt.Errorf("unknown tag name: %s", name))
+			})
+		})
+	})
+}
+
+func TestUnitGetTagIDWithValidName(t *testing.T) {
+	Convey("Given a valid tag map and tag name", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When retrieving the tag id", func() {
+			id, err := tagMap.GetTagID("three")
+
+			Convey("The id should be correct", func() {
+				So(id, ShouldEqual, "0003")
+			})
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
 func TestUnitLoadedFromFile(t *testing.T) {
 	Convey("Given a file path and tag map", t, func() {
 
@@ -181,15 +251,15 @@ func TestUnitLoadedFromFile(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		Convey("When retrieving the file path the tag map was loaded from", func() {
-			path := tagMap.LoadedFromFile()
+		Convey("When retrieving the file paths the tag map was loaded from", func() {
+			paths := tagMap.LoadedFromFile()
 
-			Convey("The file path should not be empty", func() {
-				So(path, ShouldNotBeNil)
+			Convey("The file paths should not be empty", func() {
+				So(paths, ShouldNotBeEmpty)
 			})
 
-			Convey("The path should be correct", func() {
-				So(path, ShouldEqual, path)
+			Convey("The paths should be correct", func() {
+				So(paths, ShouldResemble, []string{path})
 			})
 		})
 	})
@@ -238,7 +308,17 @@ func TestUnitParseTagDataWithTruncatedIDField(t *testing.T) {
 			})
 
 			Convey("The error should describe the problem", func() {
-				So(err, ShouldEqual, errors.New("reached EOF before parsing ID field"))
+				So(err.Error(), ShouldEqual, errors.New("reached EOF before parsing ID field").Error())
+			})
+
+			Convey("The error should be a ParseError locating the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, TruncatedID)
+				So(parseErr.FieldOffset, ShouldEqual, 0)
+				So(parseErr.ID, ShouldEqual, "0")
+				So(parseErr.Expected, ShouldEqual, 4)
+				So(parseErr.Available, ShouldEqual, 1)
 			})
 		})
 	})
@@ -263,7 +343,17 @@ func TestUnitParseTagDataWithTruncatedLengthField(t *testing.T) {
 			})
 
 			Convey("The error should describe the problem", func() {
-				So(err, ShouldEqual, fmt.Errorf("reached EOF before parsing length field for tag with id: %s", id))
+				So(err.Error(), ShouldEqual, fmt.Errorf("reached EOF before parsing length field for tag with id: %s", id).Error())
+			})
+
+			Convey("The error should be a ParseError locating the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, TruncatedLength)
+				So(parseErr.FieldOffset, ShouldEqual, 4)
+				So(parseErr.ID, ShouldEqual, id)
+				So(parseErr.Expected, ShouldEqual, 4)
+				So(parseErr.Available, ShouldEqual, 0)
 			})
 		})
 	})
@@ -288,7 +378,50 @@ func TestUnitParseTagDataWithTruncatedDataField(t *testing.T) {
 			})
 
 			Convey("The error should describe the problem", func() {
-				So(err, ShouldEqual, fmt.Errorf("reached EOF before parsing data field for tag with id: %s", id))
+				So(err.Error(), ShouldEqual, fmt.Errorf("reached EOF before parsing data field for tag with id: %s", id).Error())
+			})
+
+			Convey("The error should be a ParseError locating the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, TruncatedData)
+				So(parseErr.FieldOffset, ShouldEqual, 8)
+				So(parseErr.ID, ShouldEqual, id)
+				So(parseErr.Expected, ShouldEqual, 10)
+				So(parseErr.Available, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestUnitDecodeWithOversizedLengthField(t *testing.T) {
+	Convey("Given data declaring far more data than it holds", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder := NewDecoder(strings.NewReader("0001999999999abcd"), tagMap)
+		decoder.Dialect = &Dialect{Name: "wide", IDWidth: 4, LengthWidth: 9, LengthBase: 10, Padding: '0'}
+
+		Convey("When decoding the data", func() {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			_, err := decoder.Decode()
+			runtime.ReadMemStats(&after)
+
+			Convey("The declared length should not be allocated up front", func() {
+				So(after.TotalAlloc-before.TotalAlloc, ShouldBeLessThan, 1<<20)
+			})
+
+			Convey("The error should be a ParseError reporting the truncated data", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, TruncatedData)
+				So(parseErr.FieldOffset, ShouldEqual, 13)
+				So(parseErr.Expected, ShouldEqual, 999999999)
+				So(parseErr.Available, ShouldEqual, 4)
 			})
 		})
 	})
@@ -313,7 +446,17 @@ func TestUnitParseTagDataWithUknownIDField(t *testing.T) {
 			})
 
 			Convey("The error should describe the problem", func() {
-				So(err, ShouldEqual, fmt.Errorf("unknown id: %s", id))
+				So(err.Error(), ShouldEqual, fmt.Errorf("unknown id: %s", id).Error())
+			})
+
+			Convey("The error should be a ParseError locating the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, UnknownID)
+				So(parseErr.FieldOffset, ShouldEqual, 0)
+				So(parseErr.ID, ShouldEqual, id)
+				So(parseErr.Expected, ShouldEqual, 4)
+				So(parseErr.Available, ShouldEqual, 4)
 			})
 		})
 	})
@@ -338,7 +481,17 @@ func TestUnitParseTagDataWithNonNumericIDField(t *testing.T) {
 			})
 
 			Convey("The error should describe the problem", func() {
-				So(err, ShouldEqual, fmt.Errorf("found non-numeric id field: %s", id))
+				So(err.Error(), ShouldEqual, fmt.Errorf("found non-numeric id field: %s", id).Error())
+			})
+
+			Convey("The error should be a ParseError locating the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, NonNumericID)
+				So(parseErr.FieldOffset, ShouldEqual, 0)
+				So(parseErr.ID, ShouldEqual, id)
+				So(parseErr.Expected, ShouldEqual, 4)
+				So(parseErr.Available, ShouldEqual, 4)
 			})
 		})
 	})
@@ -363,7 +516,17 @@ func TestUnitParseTagDataWithNonNumericLengthField(t *testing.T) {
 			})
 
 			Convey("The error should describe the problem", func() {
-				So(err, ShouldEqual, fmt.Errorf("found non-numeric length field for tag with id %s: %s", id, len))
+				So(err.Error(), ShouldEqual, fmt.Errorf("found non-numeric length field for tag with id %s: %s", id, len).Error())
+			})
+
+			Convey("The error should be a ParseError locating the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, NonNumericLength)
+				So(parseErr.FieldOffset, ShouldEqual, 4)
+				So(parseErr.ID, ShouldEqual, id)
+				So(parseErr.Expected, ShouldEqual, 4)
+				So(parseErr.Available, ShouldEqual, 4)
 			})
 		})
 	})
@@ -0,0 +1,126 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// LengthUnit determines how the length field of a tag is interpreted.
+type LengthUnit int
+
+const (
+	// Bytes counts the length of tag data in bytes of the input.
+	Bytes LengthUnit = iota
+	// Characters counts the length of tag data in characters.
+	Characters
+)
+
+var lengthUnitNames = []string{"bytes", "characters"}
+
+func (u LengthUnit) String() string {
+	if int(u) < len(lengthUnitNames) {
+		return lengthUnitNames[u]
+	}
+
+	return "unknown"
+}
+
+// ParseLengthUnit returns the length unit with the given name.
+func ParseLengthUnit(name string) (LengthUnit, error) {
+	for i, n := range lengthUnitNames {
+		if n == name {
+			return LengthUnit(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("length unit must be one of %v: %s", lengthUnitNames, name)
+}
+
+// Charset is a character set that business transaction data may be encoded
+// in. Every charset other than UTF-8 uses a single byte per character.
+type Charset struct {
+	Name     string
+	encoding encoding.Encoding // nil for UTF-8
+	nel      bool              // whether U+0085 (NEL) terminates lines
+}
+
+var (
+	UTF8        = &Charset{Name: "utf-8"}
+	ISO88591    = &Charset{Name: "iso-8859-1", encoding: charmap.ISO8859_1}
+	Windows1252 = &Charset{Name: "windows-1252", encoding: charmap.Windows1252}
+	CP037       = &Charset{Name: "cp037", encoding: charmap.CodePage037, nel: true}
+)
+
+var charsets = map[string]*Charset{
+	"utf-8":        UTF8,
+	"utf8":         UTF8,
+	"iso-8859-1":   ISO88591,
+	"latin-1":      ISO88591,
+	"latin1":       ISO88591,
+	"windows-1252": Windows1252,
+	"cp1252":       Windows1252,
+	"cp037":        CP037,
+	"ibm037":       CP037,
+	"ebcdic":       CP037,
+}
+
+// LookupCharset returns the charset with the given name or alias. Names are
+// case-insensitive.
+func LookupCharset(name string) (*Charset, error) {
+	charset, ok := charsets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported charset: %s", name)
+	}
+
+	return charset, nil
+}
+
+func (c *Charset) String() string {
+	return c.Name
+}
+
+// singleByte reports whether each character of the charset is encoded as a
+// single byte.
+func (c *Charset) singleByte() bool {
+	return c != nil && c.encoding != nil
+}
+
+// countsCharacters reports whether lengths and offsets are counted in
+// characters, given the charset and length unit in use.
+func countsCharacters(c *Charset, unit LengthUnit) bool {
+	return unit == Characters || c.singleByte()
+}
+
+// measure returns the length of value in the units used for length fields.
+func measure(value string, c *Charset, unit LengthUnit) int {
+	if countsCharacters(c, unit) {
+		return utf8.RuneCountInString(value)
+	}
+
+	return len(value)
+}
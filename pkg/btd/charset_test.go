@@ -0,0 +1,187 @@
+package btd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestUnitLookupCharset(t *testing.T) {
+	Convey("Given charset names and aliases", t, func() {
+
+		Convey("When looking up the charsets", func() {
+
+			Convey("The matching charsets should be returned regardless of case", func() {
+				for name, expected := range map[string]*Charset{
+					"UTF-8":        UTF8,
+					"latin1":       ISO88591,
+					"Windows-1252": Windows1252,
+					"EBCDIC":       CP037,
+				} {
+					charset, err := LookupCharset(name)
+
+					So(err, ShouldBeNil)
+					So(charset, ShouldEqual, expected)
+				}
+			})
+		})
+
+		Convey("When looking up an unsupported charset", func() {
+			charset, err := LookupCharset("utf-16")
+
+			Convey("The error should describe the problem", func() {
+				So(charset, ShouldBeNil)
+				So(err, ShouldEqual, fmt.Errorf("unsupported charset: %s", "utf-16"))
+			})
+		})
+	})
+}
+
+func TestUnitParseLengthUnit(t *testing.T) {
+	Convey("Given an invalid length unit name", t, func() {
+
+		Convey("When parsing the name", func() {
+			_, err := ParseLengthUnit("runes")
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, errors.New("length unit must be one of [bytes characters]: runes"))
+			})
+		})
+	})
+}
+
+func TestUnitDecodeMultiByteCharacters(t *testing.T) {
+	Convey("Given UTF-8 data whose lengths are counted in characters", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := "00010008Ffordd ŵ00020001x"
+
+		Convey("When decoding with the bytes length unit", func() {
+			_, err := NewDecoder(strings.NewReader(input), tagMap).Decode()
+
+			Convey("The following tag should be misread", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When decoding with the characters length unit", func() {
+			decoder := NewDecoder(strings.NewReader(input), tagMap)
+			decoder.LengthUnit = Characters
+
+			tx, err := decoder.Decode()
+
+			Convey("The tags should be decoded correctly", func() {
+				So(err, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, TagData{
+					{"0001", "one", "0008", "Ffordd ŵ"},
+					{"0002", "two", "0001", "x"},
+				})
+			})
+
+			Convey("The offsets should be counted in characters", func() {
+				So(tx.Tags[1].Offset, ShouldEqual, 16)
+				So(decoder.CharacterOffsets(), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestUnitDecodeSingleByteCharsets(t *testing.T) {
+	Convey("Given transactions encoded in single-byte charsets", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, charset := range []*Charset{ISO88591, Windows1252, CP037} {
+			input, err := charset.encoding.NewEncoder().String("00010005Caffé\n00020001x\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Convey("When decoding "+charset.Name+" data", func() {
+				decoder := NewDecoder(strings.NewReader(input), tagMap)
+				decoder.Charset = charset
+
+				first, err1 := decoder.Decode()
+				second, err2 := decoder.Decode()
+
+				Convey("The data should be decoded to UTF-8 with lengths counted per byte", func() {
+					So(err1, ShouldBeNil)
+					So(first.TagData(), ShouldResemble, TagData{{"0001", "one", "0005", "Caffé"}})
+					So(err2, ShouldBeNil)
+					So(second.TagData(), ShouldResemble, TagData{{"0002", "two", "0001", "x"}})
+				})
+			})
+		}
+	})
+
+	Convey("Given EBCDIC data using NEL line endings", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input, err := charmap.CodePage037.NewEncoder().String("00010001a\u008500020001b")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When decoding the data", func() {
+			decoder := NewDecoder(strings.NewReader(input), tagMap)
+			decoder.Charset = CP037
+
+			first, _ := decoder.Decode()
+			second, _ := decoder.Decode()
+
+			Convey("Each line should be decoded as a separate transaction", func() {
+				So(first.TagData(), ShouldResemble, TagData{{"0001", "one", "0001", "a"}})
+				So(second.TagData(), ShouldResemble, TagData{{"0002", "two", "0001", "b"}})
+				So(decoder.Line(), ShouldEqual, 2)
+			})
+		})
+	})
+}
+
+func TestUnitEncodeWithCharset(t *testing.T) {
+	Convey("Given an encoder writing ISO-8859-1 data", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		encoder := NewEncoder(&buf, tagMap)
+		encoder.Charset = ISO88591
+
+		Convey("When encoding a value containing a non-ASCII character", func() {
+			err := encoder.Encode([]Field{{Tag: "one", Value: "Caffé"}})
+
+			Convey("The output should be encoded with lengths counted per byte", func() {
+				So(err, ShouldBeNil)
+				So(buf.Bytes(), ShouldResemble, []byte("00010005Caff\xe9\n"))
+			})
+		})
+
+		Convey("When encoding a value that cannot be represented", func() {
+			err := encoder.Encode([]Field{{Tag: "one", Value: "ŵ"}})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldNotBeNil)
+				So(buf.Len(), ShouldEqual, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,394 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// ErrEndOfTransaction is returned by Decoder.Next once every tag of the
+// current transaction has been read.
+var ErrEndOfTransaction = errors.New("end of transaction")
+
+// Decoder reads business transaction data directly from an input stream. Each
+// line of the input is treated as a single, complete transaction; blank lines
+// are skipped.
+type Decoder struct {
+	// Lenient causes Decode to collect parse errors in Transaction.Errors
+	// rather than failing, returning every tag parsed successfully. Unknown
+	// tag ids are skipped; any other error ends the transaction.
+	Lenient bool
+
+	// UnknownTags determines how tags with an id missing from the tag map
+	// are handled.
+	UnknownTags UnknownTagPolicy
+
+	// Charset is the character set the input is encoded in; nil means UTF-8.
+	// Data is always returned as UTF-8.
+	Charset *Charset
+
+	// LengthUnit determines whether length fields, and the offsets reported
+	// in tags and errors, count bytes or characters. Lengths are always
+	// counted in characters for single-byte charsets.
+	LengthUnit LengthUnit
+
+	// Dialect describes the layout of the id and length fields; nil means
+	// the Standard dialect.
+	Dialect *Dialect
+
+	// Types, if set, is used to detect the type of each transaction decoded.
+	Types *TransactionTypes
+
+	// Extractor, if set, is used to extract the data of each transaction
+	// from a line of a log, after decoding it from Charset. Lines that do not
+	// match are skipped.
+	Extractor *Extractor
+
+	src    io.Reader
+	log    *logReader
+	r      *bufio.Reader
+	tagMap TagMap
+
+	lines  int  // number of line endings consumed so far
+	line   int  // line number of the current (or most recent) transaction
+	inTx   bool // whether a transaction is currently being read
+	offset int  // offset of the next unread byte or character within the transaction
+	raw    []byte
+
+	warnings []*ParseError
+}
+
+// NewDecoder returns a Decoder that reads from r and resolves tag names using
+// tagMap.
+func NewDecoder(r io.Reader, tagMap TagMap) *Decoder {
+	return &Decoder{
+		src:    r,
+		tagMap: tagMap,
+	}
+}
+
+// Decode reads the next complete transaction from the input. It returns
+// io.EOF when no transactions remain. If a transaction cannot be parsed the
+// remainder of its line is discarded, so Decode may be called again to
+// continue with the next transaction.
+func (d *Decoder) Decode() (*Transaction, error) {
+	tx := &Transaction{}
+
+	for {
+		tag, err := d.Next()
+		if err == ErrEndOfTransaction {
+			return d.finish(tx), nil
+		}
+
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) && d.Lenient {
+			tx.Errors = append(tx.Errors, parseErr)
+
+			if !d.inTx {
+				return d.finish(tx), nil
+			}
+			continue
+		}
+
+		if err != nil {
+			if d.inTx {
+				d.endTransaction()
+			}
+			return nil, err
+		}
+
+		tx.Tags = append(tx.Tags, tag)
+	}
+}
+
+// finish completes a decoded transaction, arranging its tags into groups,
+// detecting its type and adding the metadata of the log line it was
+// extracted from.
+func (d *Decoder) finish(tx *Transaction) *Transaction {
+	tx.Line = d.line
+	tx.Warnings = d.warnings
+	tx.Root = buildGroups(tx.Tags, d.tagMap.Groups())
+
+	if d.Types != nil {
+		tx.Type = d.Types.Detect(tx)
+	}
+
+	if d.log != nil {
+		tx.Metadata = d.log.takeMetadata(d.line)
+	}
+
+	return tx
+}
+
+// Next reads the next tag of the current transaction, starting a new
+// transaction if none is in progress. It returns ErrEndOfTransaction after
+// the last tag of a transaction and io.EOF when the input is exhausted.
+// Problems with the data are reported as a *ParseError; if the error is not
+// recoverable the remainder of the transaction is discarded. Tags with an
+// unknown id are handled according to the UnknownTags policy.
+func (d *Decoder) Next() (Tag, error) {
+	d.init()
+
+	if !d.inTx {
+		if err := d.skipBlankLines(); err != nil {
+			return Tag{}, err
+		}
+
+		d.inTx = true
+		d.line = d.lines + 1
+		d.offset = 0
+		d.raw = d.raw[:0]
+		d.warnings = nil
+	}
+
+	for {
+		if d.atLineEnd() {
+			d.endTransaction()
+			return Tag{}, ErrEndOfTransaction
+		}
+
+		tag, err := parseTag(d)
+		if err == nil {
+			return tag, nil
+		}
+
+		parseErr, ok := err.(*ParseError)
+		if !ok || !parseErr.recoverable() {
+			d.endTransaction()
+			return Tag{}, err
+		}
+
+		switch d.UnknownTags {
+		case UnknownTagSkip:
+			continue
+		case UnknownTagWarn:
+			d.warnings = append(d.warnings, parseErr)
+			fallthrough
+		case UnknownTagPlaceholder:
+			tag.Name = PlaceholderName(tag.ID)
+			return tag, nil
+		}
+
+		return Tag{}, err
+	}
+}
+
+// Reset discards any buffered data and state, and switches the decoder to
+// read from r as if it had been newly created, counting lines from the start
+// of r. The decoder's configuration is unchanged.
+func (d *Decoder) Reset(r io.Reader) {
+	d.src = r
+	d.r = nil
+	d.log = nil
+	d.lines, d.line = 0, 0
+	d.inTx = false
+	d.offset = 0
+	d.raw = d.raw[:0]
+	d.warnings = nil
+}
+
+// Warnings returns the unknown tag ids reported for the current transaction
+// under the UnknownTagWarn policy.
+func (d *Decoder) Warnings() []*ParseError {
+	return d.warnings
+}
+
+// More reports whether another transaction remains in the input.
+func (d *Decoder) More() bool {
+	d.init()
+
+	if d.inTx {
+		return true
+	}
+
+	return d.skipBlankLines() == nil
+}
+
+// Line returns the line number of the transaction most recently read.
+func (d *Decoder) Line() int {
+	return d.line
+}
+
+// Raw returns the data of the transaction most recently read, as it appeared
+// in the input. The remainder of a transaction is included once it has been
+// discarded following an error.
+func (d *Decoder) Raw() string {
+	return string(d.raw)
+}
+
+// CharacterOffsets reports whether offsets and lengths are counted in
+// characters of the decoded data rather than bytes.
+func (d *Decoder) CharacterOffsets() bool {
+	return countsCharacters(d.Charset, d.LengthUnit)
+}
+
+// dialect returns the dialect in use.
+func (d *Decoder) dialect() *Dialect {
+	if d.Dialect == nil {
+		return Standard
+	}
+
+	return d.Dialect
+}
+
+// measure returns the length of value in the units offsets are counted in.
+func (d *Decoder) measure(value string) int {
+	return measure(value, d.Charset, d.LengthUnit)
+}
+
+// init prepares the input for reading, extracting transactions from log
+// lines and decoding it from the configured charset if necessary.
+func (d *Decoder) init() {
+	if d.r != nil {
+		return
+	}
+
+	src := d.src
+
+	if d.Charset.singleByte() {
+		src = d.Charset.encoding.NewDecoder().Reader(src)
+	}
+
+	// Log lines are matched once decoded, so that patterns and metadata are
+	// in the same text as the transaction data.
+	if d.Extractor != nil {
+		d.log = newLogReader(src, d.Extractor, d.Charset != nil && d.Charset.nel)
+		src = d.log
+	}
+
+	d.r = bufio.NewReader(src)
+}
+
+// maxFieldCapacity limits the buffer allocated up front by readField, since a
+// length field may declare far more data than the transaction holds.
+const maxFieldCapacity = 4096
+
+// readField reads exactly length bytes or characters from the current
+// transaction. It returns an error, along with any data read, if the end of
+// the line or input is reached first.
+func (d *Decoder) readField(length int) (string, error) {
+	data := make([]byte, 0, min(length, maxFieldCapacity))
+	characters := d.CharacterOffsets()
+
+	for n := 0; n < length; n++ {
+		if d.atLineEnd() {
+			return string(data), fmt.Errorf("not enough data remaining to read: %d", length)
+		}
+
+		start := len(data)
+
+		if characters {
+			r, _, err := d.r.ReadRune()
+			if err != nil {
+				return string(data), err
+			}
+			data = utf8.AppendRune(data, r)
+		} else {
+			b, err := d.r.ReadByte()
+			if err != nil {
+				return string(data), err
+			}
+			data = append(data, b)
+		}
+
+		d.raw = append(d.raw, data[start:]...)
+		d.offset++
+	}
+
+	return string(data), nil
+}
+
+// atLineEnd reports whether the next unread bytes terminate the current line,
+// or the input has been exhausted.
+func (d *Decoder) atLineEnd() bool {
+	return d.lineEndingLength() != 0
+}
+
+// lineEndingLength returns the number of bytes making up the line ending at
+// the current position, zero if the current position is not at the end of a
+// line, or -1 at the end of the input.
+func (d *Decoder) lineEndingLength() int {
+	b, err := d.r.Peek(1)
+	if err != nil {
+		return -1
+	}
+
+	switch b[0] {
+	case '\n':
+		return 1
+	case '\r':
+		if b, err = d.r.Peek(2); err == nil && b[1] == '\n' {
+			return 2
+		}
+	case 0xc2:
+		if b, err = d.r.Peek(2); err == nil && b[1] == 0x85 && d.Charset != nil && d.Charset.nel {
+			return 2
+		}
+	}
+
+	return 0
+}
+
+// endTransaction discards the remainder of the current line, including its
+// line ending.
+func (d *Decoder) endTransaction() {
+	d.inTx = false
+
+	for !d.atLineEnd() {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return
+		}
+
+		d.raw = append(d.raw, b)
+	}
+
+	d.skipLineEnding()
+}
+
+// skipLineEnding consumes the line ending at the current position.
+func (d *Decoder) skipLineEnding() {
+	if n := d.lineEndingLength(); n > 0 {
+		d.r.Discard(n)
+		d.lines++
+	}
+}
+
+// skipBlankLines consumes empty lines preceding the next transaction. It
+// returns io.EOF if the input contains no further transactions.
+func (d *Decoder) skipBlankLines() error {
+	for {
+		if _, err := d.r.Peek(1); err != nil {
+			return io.EOF
+		}
+
+		if !d.atLineEnd() {
+			return nil
+		}
+
+		d.skipLineEnding()
+	}
+}
@@ -0,0 +1,292 @@
+package btd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDecodeWithMultipleTransactions(t *testing.T) {
+	Convey("Given a decoder reading multiple transactions separated by blank lines", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := "00010004abcd00020002ef\n\n\r\n00030001g\r\n"
+		decoder := NewDecoder(strings.NewReader(input), tagMap)
+
+		Convey("When decoding the first transaction", func() {
+			tx, err := decoder.Decode()
+
+			Convey("The tag data should be correct", func() {
+				So(tx.TagData(), ShouldResemble, TagData{
+					{"0001", "one", "0004", "abcd"},
+					{"0002", "two", "0002", "ef"},
+				})
+			})
+
+			Convey("The tag offsets should be correct", func() {
+				So(tx.Tags[0].Offset, ShouldEqual, 0)
+				So(tx.Tags[1].Offset, ShouldEqual, 12)
+			})
+
+			Convey("The line number should be correct", func() {
+				So(decoder.Line(), ShouldEqual, 1)
+			})
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("And decoding the second transaction", func() {
+				tx, err := decoder.Decode()
+
+				Convey("The tag data should be correct", func() {
+					So(tx.TagData(), ShouldResemble, TagData{{"0003", "three", "0001", "g"}})
+				})
+
+				Convey("The line number should skip the blank lines", func() {
+					So(decoder.Line(), ShouldEqual, 4)
+				})
+
+				Convey("The error should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("And decoding again", func() {
+					tx, err := decoder.Decode()
+
+					Convey("The transaction should be nil", func() {
+						So(tx, ShouldBeNil)
+					})
+
+					Convey("The error should be io.EOF", func() {
+						So(err, ShouldEqual, io.EOF)
+					})
+				})
+			})
+		})
+	})
+}
+
+func TestUnitDecoderReset(t *testing.T) {
+	Convey("Given a decoder that has read to the end of its input", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder := NewDecoder(strings.NewReader("\n00010001a\n"), tagMap)
+		decoder.Lenient = true
+
+		if _, err := decoder.Decode(); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When resetting the decoder to read new input", func() {
+			decoder.Reset(strings.NewReader("00020001b\n"))
+			tx, err := decoder.Decode()
+
+			Convey("The new input should be decoded", func() {
+				So(err, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, TagData{{"0002", "two", "0001", "b"}})
+			})
+
+			Convey("Lines should be counted from the start of the new input", func() {
+				So(tx.Line, ShouldEqual, 1)
+			})
+
+			Convey("The configuration should be kept", func() {
+				So(decoder.Lenient, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestUnitDecodeContinuesAfterInvalidTransaction(t *testing.T) {
+	Convey("Given a decoder reading an invalid transaction followed by a valid one", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := "00010010abcd\n00010004abcd\n"
+		decoder := NewDecoder(strings.NewReader(input), tagMap)
+
+		Convey("When decoding the first transaction", func() {
+			tx, err := decoder.Decode()
+
+			Convey("The transaction should be nil", func() {
+				So(tx, ShouldBeNil)
+			})
+
+			Convey("The error should describe the problem", func() {
+				So(err.Error(), ShouldEqual, fmt.Sprintf("reached EOF before parsing data field for tag with id: %s", "0001"))
+			})
+
+			Convey("The raw transaction data should be available", func() {
+				So(decoder.Raw(), ShouldEqual, "00010010abcd")
+			})
+
+			Convey("And decoding the second transaction", func() {
+				tx, err := decoder.Decode()
+
+				Convey("The tag data should be correct", func() {
+					So(tx.TagData(), ShouldResemble, TagData{{"0001", "one", "0004", "abcd"}})
+				})
+
+				Convey("The line number should be correct", func() {
+					So(decoder.Line(), ShouldEqual, 2)
+				})
+
+				Convey("The error should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func TestUnitNextReadsIndividualTags(t *testing.T) {
+	Convey("Given a decoder reading a single transaction", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder := NewDecoder(strings.NewReader("00010001a00020001b"), tagMap)
+
+		Convey("When reading each tag in turn", func() {
+			first, err1 := decoder.Next()
+			second, err2 := decoder.Next()
+			_, err3 := decoder.Next()
+			_, err4 := decoder.Next()
+
+			Convey("The tags should be returned in order", func() {
+				So(first.ID, ShouldEqual, "0001")
+				So(first.Value, ShouldEqual, "a")
+				So(second.ID, ShouldEqual, "0002")
+				So(second.Value, ShouldEqual, "b")
+				So(err1, ShouldBeNil)
+				So(err2, ShouldBeNil)
+			})
+
+			Convey("The end of the transaction should be reported", func() {
+				So(err3, ShouldEqual, ErrEndOfTransaction)
+			})
+
+			Convey("The end of the input should be reported", func() {
+				So(err4, ShouldEqual, io.EOF)
+			})
+		})
+	})
+}
+
+func TestUnitParseTagDataWithMultipleTransactions(t *testing.T) {
+	Convey("Given a tag map and BTD data string containing more than one transaction", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When parsing the BTD data string", func() {
+			tagData, err := tagMap.ParseTagData("00010001a\n00020001b")
+
+			Convey("The tag data should be nil", func() {
+				So(tagData, ShouldBeNil)
+			})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, errors.New("data string contains more than one transaction"))
+			})
+		})
+	})
+}
+
+func TestUnitDecodeLeniently(t *testing.T) {
+	Convey("Given a lenient decoder reading a transaction containing problems", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := "00010001a00990001b00020001c0003xx\n00010001a\n"
+		decoder := NewDecoder(strings.NewReader(input), tagMap)
+		decoder.Lenient = true
+
+		Convey("When decoding the transaction", func() {
+			tx, err := decoder.Decode()
+
+			Convey("The successfully parsed tags should be returned", func() {
+				So(tx.TagData(), ShouldResemble, TagData{
+					{"0001", "one", "0001", "a"},
+					{"0002", "two", "0001", "c"},
+				})
+			})
+
+			Convey("Every problem should be collected", func() {
+				So(len(tx.Errors), ShouldEqual, 2)
+
+				So(tx.Errors[0].Kind, ShouldEqual, UnknownID)
+				So(tx.Errors[0].Offset, ShouldEqual, 9)
+				So(tx.Errors[0].ID, ShouldEqual, "0099")
+
+				So(tx.Errors[1].Kind, ShouldEqual, TruncatedLength)
+				So(tx.Errors[1].Offset, ShouldEqual, 27)
+				So(tx.Errors[1].ID, ShouldEqual, "0003")
+				So(tx.Errors[1].Error(), ShouldEqual, "reached EOF before parsing length field for tag with id: 0003")
+			})
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("And decoding the next transaction", func() {
+				tx, err := decoder.Decode()
+
+				Convey("The transaction should be parsed from the next line", func() {
+					So(tx.TagData(), ShouldResemble, TagData{{"0001", "one", "0001", "a"}})
+					So(tx.Errors, ShouldBeEmpty)
+					So(decoder.Line(), ShouldEqual, 2)
+					So(err, ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func TestUnitDecodeLenientlyWithPartialID(t *testing.T) {
+	Convey("Given a lenient decoder reading a transaction with a truncated id", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder := NewDecoder(strings.NewReader("00010001a00"), tagMap)
+		decoder.Lenient = true
+
+		Convey("When decoding the transaction", func() {
+			tx, err := decoder.Decode()
+
+			Convey("The error should record the partial id", func() {
+				So(err, ShouldBeNil)
+				So(len(tx.Errors), ShouldEqual, 1)
+				So(tx.Errors[0].Kind, ShouldEqual, TruncatedID)
+				So(tx.Errors[0].ID, ShouldEqual, "00")
+				So(tx.Errors[0].Offset, ShouldEqual, 9)
+			})
+		})
+	})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dialect describes the layout of the id and length fields that precede the
+// data of each tag.
+type Dialect struct {
+	Name        string
+	IDWidth     int    // number of characters in the id field
+	LengthWidth int    // number of characters in the length field
+	LengthBase  int    // numeric base of the length field
+	Padding     rune   // character used to left-pad the id and length fields
+	Separator   string // optional separator between consecutive tags
+}
+
+// Standard is the dialect used by current gateway releases: a 4-digit id
+// followed by a 4-digit decimal length, both zero-padded.
+var Standard = &Dialect{
+	Name:        "standard",
+	IDWidth:     4,
+	LengthWidth: 4,
+	LengthBase:  10,
+	Padding:     '0',
+}
+
+var dialects = map[string]*Dialect{
+	Standard.Name: Standard,
+}
+
+// LookupDialect returns the built-in dialect with the given name.
+func LookupDialect(name string) (*Dialect, error) {
+	dialect, ok := dialects[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown dialect: %s", name)
+	}
+
+	return dialect, nil
+}
+
+// Validate reports whether the dialect's settings can be used to parse and
+// encode data.
+func (d *Dialect) Validate() error {
+	switch {
+	case d.IDWidth < 1 || d.IDWidth > 9:
+		return fmt.Errorf("dialect %s: id width must be between 1 and 9: %d", d.Name, d.IDWidth)
+	case d.LengthWidth < 1 || d.LengthWidth > 9:
+		return fmt.Errorf("dialect %s: length width must be between 1 and 9: %d", d.Name, d.LengthWidth)
+	case d.LengthBase < 2 || d.LengthBase > 36:
+		return fmt.Errorf("dialect %s: length base must be between 2 and 36: %d", d.Name, d.LengthBase)
+	case d.Padding == 0 || d.Padding == utf8.RuneError:
+		return fmt.Errorf("dialect %s: padding must be a single character", d.Name)
+	case strings.ContainsAny(d.Separator, "\r\n"):
+		return fmt.Errorf("dialect %s: separator cannot contain line endings", d.Name)
+	}
+
+	return nil
+}
+
+func (d *Dialect) String() string {
+	return d.Name
+}
+
+// MaxID returns the largest tag id that fits in the id field.
+func (d *Dialect) MaxID() uint64 {
+	return uint64(math.Pow10(d.IDWidth)) - 1
+}
+
+// MaxLength returns the largest length that fits in the length field.
+func (d *Dialect) MaxLength() uint64 {
+	return uint64(math.Pow(float64(d.LengthBase), float64(d.LengthWidth))) - 1
+}
+
+// parseID returns the numeric value of an id field.
+func (d *Dialect) parseID(field string) (uint64, error) {
+	return d.parseField(field, 10)
+}
+
+// parseLength returns the numeric value of a length field.
+func (d *Dialect) parseLength(field string) (uint64, error) {
+	return d.parseField(field, d.LengthBase)
+}
+
+func (d *Dialect) parseField(field string, base int) (uint64, error) {
+	digits := strings.TrimLeft(field, string(d.Padding))
+	if len(digits) == 0 && len(field) > 0 {
+		return 0, nil
+	}
+
+	num, err := strconv.ParseUint(digits, base, 32)
+	if err != nil {
+		return 0, errors.New("unable to parse UInt value")
+	}
+
+	return num, nil
+}
+
+// formatID returns id as a padded id field.
+func (d *Dialect) formatID(id uint64) string {
+	return d.pad(strconv.FormatUint(id, 10), d.IDWidth)
+}
+
+// formatLength returns length as a padded length field.
+func (d *Dialect) formatLength(length uint64) string {
+	return d.pad(strings.ToUpper(strconv.FormatUint(length, d.LengthBase)), d.LengthWidth)
+}
+
+func (d *Dialect) pad(value string, width int) string {
+	if n := width - len(value); n > 0 {
+		return strings.Repeat(string(d.Padding), n) + value
+	}
+
+	return value
+}
+
+// normaliseID returns id without leading zeros, so that ids of different
+// widths refer to the same tag.
+func normaliseID(id string) string {
+	if trimmed := strings.TrimLeft(id, "0"); len(trimmed) > 0 {
+		return trimmed
+	}
+
+	return "0"
+}
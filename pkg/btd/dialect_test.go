@@ -0,0 +1,150 @@
+package btd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLookupDialect(t *testing.T) {
+	Convey("Given the name of the standard dialect", t, func() {
+
+		Convey("When looking up the dialect", func() {
+			dialect, err := LookupDialect("standard")
+
+			Convey("The standard dialect should be returned", func() {
+				So(err, ShouldBeNil)
+				So(dialect, ShouldEqual, Standard)
+				So(dialect.MaxID(), ShouldEqual, 9999)
+				So(dialect.MaxLength(), ShouldEqual, 9999)
+			})
+		})
+	})
+
+	Convey("Given the name of an unknown dialect", t, func() {
+
+		Convey("When looking up the dialect", func() {
+			_, err := LookupDialect("legacy")
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, fmt.Errorf("unknown dialect: %s", "legacy"))
+			})
+		})
+	})
+}
+
+func TestUnitValidateDialect(t *testing.T) {
+	Convey("Given a dialect with an invalid length base", t, func() {
+		dialect := &Dialect{Name: "invalid", IDWidth: 4, LengthWidth: 4, LengthBase: 1, Padding: '0'}
+
+		Convey("When validating the dialect", func() {
+			err := dialect.Validate()
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, errors.New("dialect invalid: length base must be between 2 and 36: 1"))
+			})
+		})
+	})
+}
+
+func TestUnitDecodeWithDialect(t *testing.T) {
+	Convey("Given data using 3-digit ids, 5-digit space-padded lengths and a separator", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dialect := &Dialect{Name: "legacy", IDWidth: 3, LengthWidth: 5, LengthBase: 10, Padding: ' ', Separator: "|"}
+
+		Convey("When decoding valid data", func() {
+			decoder := NewDecoder(strings.NewReader("  1    3abc| 10    1x"), tagMap)
+			decoder.Dialect = dialect
+
+			tx, err := decoder.Decode()
+
+			Convey("The ids should be resolved regardless of width", func() {
+				So(err, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, TagData{
+					{"  1", "one", "    3", "abc"},
+					{" 10", "ten", "    1", "x"},
+				})
+			})
+
+			Convey("The offsets should account for the separator", func() {
+				So(tx.Tags[1].Offset, ShouldEqual, 12)
+			})
+		})
+
+		Convey("When decoding data with a missing separator", func() {
+			decoder := NewDecoder(strings.NewReader("  1    3abc; 10    1x"), tagMap)
+			decoder.Dialect = dialect
+
+			_, err := decoder.Decode()
+
+			Convey("The error should describe the problem", func() {
+				var parseErr *ParseError
+				So(errors.As(err, &parseErr), ShouldBeTrue)
+				So(parseErr.Kind, ShouldEqual, MissingSeparator)
+				So(parseErr.FieldOffset, ShouldEqual, 11)
+			})
+		})
+	})
+
+	Convey("Given data using hexadecimal lengths", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder := NewDecoder(strings.NewReader("000100"+"10abcdefghijklmnop"), tagMap)
+		decoder.Dialect = &Dialect{Name: "hex", IDWidth: 4, LengthWidth: 4, LengthBase: 16, Padding: '0'}
+
+		Convey("When decoding the data", func() {
+			tx, err := decoder.Decode()
+
+			Convey("The length should be interpreted in base 16", func() {
+				So(err, ShouldBeNil)
+				So(tx.Tags[0].DeclaredLength, ShouldEqual, 16)
+				So(tx.Tags[0].Value, ShouldEqual, "abcdefghijklmnop")
+			})
+		})
+	})
+}
+
+func TestUnitEncodeWithDialect(t *testing.T) {
+	Convey("Given an encoder using a 5-digit length dialect", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		encoder := NewEncoder(&buf, tagMap)
+		encoder.Dialect = &Dialect{Name: "long", IDWidth: 3, LengthWidth: 5, LengthBase: 10, Padding: '0', Separator: "|"}
+
+		Convey("When encoding a value longer than 9999 bytes", func() {
+			value := strings.Repeat("x", 10000)
+			err := encoder.Encode([]Field{{Tag: "one", Value: value}, {Tag: "0010", Value: "y"}})
+
+			Convey("The fields should be padded to the dialect's widths", func() {
+				So(err, ShouldBeNil)
+				So(buf.String(), ShouldEqual, "00110000"+value+"|01000001y\n")
+			})
+		})
+
+		Convey("When encoding a tag id wider than the id field", func() {
+			_, err := encoder.EncodeToString([]Field{{Tag: "1000", Value: "x"}})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, errors.New("tag id out of range: 1000"))
+			})
+		})
+	})
+}
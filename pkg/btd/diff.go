@@ -0,0 +1,142 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// ChangeKind identifies the kind of change between two versions of a tag map.
+type ChangeKind int
+
+const (
+	Added      ChangeKind = iota // the id is only mapped in the new tag map
+	Removed                      // the id is only mapped in the old tag map
+	Renamed                      // the id is mapped to a name not used elsewhere in either tag map
+	Reassigned                   // the id is mapped to a name used by another id in either tag map
+)
+
+var changeKindNames = [...]string{
+	Added:      "added",
+	Removed:    "removed",
+	Renamed:    "renamed",
+	Reassigned: "reassigned",
+}
+
+func (k ChangeKind) String() string {
+	if int(k) < len(changeKindNames) {
+		return changeKindNames[k]
+	}
+
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Breaking reports whether data parsed using the old tag map could be read
+// differently using the new tag map.
+func (k ChangeKind) Breaking() bool {
+	return k != Added
+}
+
+// TagMapChange is a single change between two versions of a tag map.
+type TagMapChange struct {
+	Kind    ChangeKind
+	ID      string // id as written in the new tag map, or the old if removed
+	OldName string
+	NewName string
+	OldLine int
+	NewLine int
+}
+
+// DiffTagMaps returns the changes between the old and new versions of a tag
+// map, ordered by id. Ids are compared numerically, so an id that is merely
+// written differently is not reported as changed.
+func DiffTagMaps(oldMap, newMap TagMap) []TagMapChange {
+	var changes []TagMapChange
+
+	oldNames := namesInUse(oldMap)
+	newNames := namesInUse(newMap)
+
+	for _, def := range newMap.Tags() {
+		if superseded(newMap, def) {
+			continue
+		}
+
+		prev, ok := oldMap.Lookup(def.ID)
+		if !ok {
+			changes = append(changes, TagMapChange{Kind: Added, ID: def.ID, NewName: def.Name, NewLine: def.Line})
+			continue
+		}
+
+		if prev.Name == def.Name {
+			continue
+		}
+
+		kind := Renamed
+		if id, ok := oldNames[def.Name]; ok && id != normaliseID(def.ID) {
+			kind = Reassigned
+		}
+		if id, ok := newNames[prev.Name]; ok && id != normaliseID(def.ID) {
+			kind = Reassigned
+		}
+
+		changes = append(changes, TagMapChange{Kind: kind, ID: def.ID, OldName: prev.Name, NewName: def.Name, OldLine: prev.Line, NewLine: def.Line})
+	}
+
+	for _, def := range oldMap.Tags() {
+		if superseded(oldMap, def) {
+			continue
+		}
+
+		if _, ok := newMap.Lookup(def.ID); !ok {
+			changes = append(changes, TagMapChange{Kind: Removed, ID: def.ID, OldName: def.Name, OldLine: def.Line})
+		}
+	}
+
+	slices.SortStableFunc(changes, func(a, b TagMapChange) int {
+		x, y := normaliseID(a.ID), normaliseID(b.ID)
+		return cmp.Or(cmp.Compare(len(x), len(y)), cmp.Compare(x, y))
+	})
+
+	return changes
+}
+
+// namesInUse returns the normalised id each name is mapped from.
+func namesInUse(tagMap TagMap) map[string]string {
+	names := make(map[string]string)
+
+	for _, def := range tagMap.Tags() {
+		if !superseded(tagMap, def) {
+			names[def.Name] = normaliseID(def.ID)
+		}
+	}
+
+	return names
+}
+
+// superseded reports whether def is overridden by a later mapping of the same
+// id in tagMap.
+func superseded(tagMap TagMap, def TagDef) bool {
+	current, _ := tagMap.Lookup(def.ID)
+	return current.Line != def.Line || current.Source != def.Source
+}
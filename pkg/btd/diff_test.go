@@ -0,0 +1,48 @@
+package btd
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDiffTagMaps(t *testing.T) {
+	Convey("Given two versions of a tag map", t, func() {
+
+		oldMap, err := LoadTagMap("testdata/tagmap_old.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newMap, err := LoadTagMap("testdata/tagmap_new.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When comparing the tag maps", func() {
+			changes := DiffTagMaps(oldMap, newMap)
+
+			Convey("Each change should be reported in id order", func() {
+				So(changes, ShouldResemble, []TagMapChange{
+					{Kind: Renamed, ID: "0003", OldName: "three", NewName: "third", OldLine: 3, NewLine: 3},
+					{Kind: Reassigned, ID: "0004", OldName: "four", NewName: "five", OldLine: 4, NewLine: 4},
+					{Kind: Removed, ID: "0005", OldName: "five", OldLine: 5},
+					{Kind: Added, ID: "0006", NewName: "six", NewLine: 5},
+				})
+			})
+
+			Convey("Only added ids should not be breaking", func() {
+				So(changes[0].Kind.Breaking(), ShouldBeTrue)
+				So(changes[3].Kind.Breaking(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When comparing a tag map with itself", func() {
+			changes := DiffTagMaps(oldMap, oldMap)
+
+			Convey("No changes should be reported", func() {
+				So(changes, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Field is a single tag to be encoded. Tag may hold either a numeric tag id
+// or an XML tag name from the tag map.
+type Field struct {
+	Tag   string
+	Value string
+}
+
+// Encoder writes business transaction data to an output stream.
+type Encoder struct {
+	// Charset is the character set the output is encoded in; nil means UTF-8.
+	Charset *Charset
+
+	// LengthUnit determines whether length fields count bytes or characters.
+	// Lengths are always counted in characters for single-byte charsets.
+	LengthUnit LengthUnit
+
+	// Dialect describes the layout of the id and length fields; nil means
+	// the Standard dialect.
+	Dialect *Dialect
+
+	w      io.Writer
+	tagMap TagMap
+}
+
+// NewEncoder returns an Encoder that writes to w and resolves XML tag names
+// using tagMap.
+func NewEncoder(w io.Writer, tagMap TagMap) *Encoder {
+	return &Encoder{
+		w:      w,
+		tagMap: tagMap,
+	}
+}
+
+// Encode writes fields to the output as a single transaction terminated by
+// a newline, encoded in the configured charset.
+func (e *Encoder) Encode(fields []Field) error {
+	data, err := e.EncodeToString(fields)
+	if err != nil {
+		return err
+	}
+
+	data += "\n"
+
+	if e.Charset.singleByte() {
+		if data, err = e.Charset.encoding.NewEncoder().String(data); err != nil {
+			return fmt.Errorf("unable to encode data as %s: %w", e.Charset, err)
+		}
+	}
+
+	_, err = io.WriteString(e.w, data)
+	return err
+}
+
+// EncodeToString returns fields encoded as a single transaction string. The
+// string itself is always UTF-8, with lengths counted in the units that apply
+// to the configured charset.
+func (e *Encoder) EncodeToString(fields []Field) (string, error) {
+	if len(fields) == 0 {
+		return "", errors.New("no fields to encode")
+	}
+
+	var sb strings.Builder
+
+	for i, field := range fields {
+		tag, err := e.encodeTag(field)
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 {
+			sb.WriteString(e.dialect().Separator)
+		}
+		sb.WriteString(tag)
+	}
+
+	return sb.String(), nil
+}
+
+func (e *Encoder) encodeTag(field Field) (string, error) {
+	dialect := e.dialect()
+
+	id, err := resolveTagID(e.tagMap, dialect, field.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	// Each transaction occupies a single line, so a value containing a line
+	// terminator could not be decoded.
+	if strings.ContainsAny(field.Value, "\n\r\u0085") {
+		return "", fmt.Errorf("value for tag with id %s contains a line terminator", id)
+	}
+
+	length := measure(field.Value, e.Charset, e.LengthUnit)
+
+	if uint64(length) > dialect.MaxLength() {
+		unit := "bytes"
+		if countsCharacters(e.Charset, e.LengthUnit) {
+			unit = "characters"
+		}
+		return "", fmt.Errorf("value too long for tag with id %s: %d %s", id, length, unit)
+	}
+
+	return id + dialect.formatLength(uint64(length)) + field.Value, nil
+}
+
+func (e *Encoder) dialect() *Dialect {
+	if e.Dialect == nil {
+		return Standard
+	}
+
+	return e.Dialect
+}
+
+// resolveTagID returns the padded id field for tag, which may be either a
+// numeric id or an XML tag name.
+func resolveTagID(t TagMap, dialect *Dialect, tag string) (string, error) {
+	if len(tag) == 0 {
+		return "", errors.New("tag cannot be empty")
+	}
+
+	num, err := parseUIntValue(tag)
+	if err == nil {
+		if num > dialect.MaxID() {
+			return "", fmt.Errorf("tag id out of range: %s", tag)
+		}
+
+		id := dialect.formatID(num)
+
+		if _, err := t.GetTagName(tag); err != nil {
+			return "", fmt.Errorf("unknown id: %s", id)
+		}
+
+		return id, nil
+	}
+
+	id, err := t.GetTagID(tag)
+	if err != nil {
+		return "", err
+	}
+
+	if num, err = parseUIntValue(id); err != nil {
+		return "", fmt.Errorf("found non-numeric id in tag map for tag %s: %s", tag, id)
+	}
+
+	if num > dialect.MaxID() {
+		return "", fmt.Errorf("tag id out of range for tag %s: %s", tag, id)
+	}
+
+	return dialect.formatID(num), nil
+}
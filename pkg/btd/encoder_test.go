@@ -0,0 +1,154 @@
+package btd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitEncodeWithNamesAndIDs(t *testing.T) {
+	Convey("Given a tag map and fields identified by tag name and id", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := []Field{
+			{Tag: "one", Value: "abcd"},
+			{Tag: "2", Value: ""},
+			{Tag: "0010", Value: "Crown Way"},
+		}
+
+		Convey("When encoding the fields", func() {
+			var buf bytes.Buffer
+			err := NewEncoder(&buf, tagMap).Encode(fields)
+
+			Convey("The output should contain zero-padded id and length fields", func() {
+				So(buf.String(), ShouldEqual, "00010004abcd0002000000100009Crown Way\n")
+			})
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The output should decode to the original fields", func() {
+				tagData, err := tagMap.ParseTagData(strings.TrimSuffix(buf.String(), "\n"))
+
+				So(err, ShouldBeNil)
+				So(tagData, ShouldResemble, TagData{
+					{"0001", "one", "0004", "abcd"},
+					{"0002", "two", "0000", ""},
+					{"0010", "ten", "0009", "Crown Way"},
+				})
+			})
+		})
+	})
+}
+
+func TestUnitEncodeWithLineTerminators(t *testing.T) {
+	Convey("Given a tag map", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, value := range []string{"a\nb", "a\rb", "a\u0085b"} {
+			Convey(fmt.Sprintf("When encoding the value %q", value), func() {
+				var buf bytes.Buffer
+				err := NewEncoder(&buf, tagMap).Encode([]Field{{Tag: "two", Value: value}})
+
+				Convey("An error should be returned and nothing written", func() {
+					So(err, ShouldEqual, errors.New("value for tag with id 0002 contains a line terminator"))
+					So(buf.Len(), ShouldEqual, 0)
+				})
+			})
+		}
+
+		Convey("When encoding a value containing other control characters", func() {
+			var buf bytes.Buffer
+			err := NewEncoder(&buf, tagMap).Encode([]Field{{Tag: "two", Value: "a\tb"}})
+			So(err, ShouldBeNil)
+
+			tx, decodeErr := NewDecoder(&buf, tagMap).Decode()
+
+			Convey("The output should decode to the original value", func() {
+				So(decodeErr, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, TagData{{"0002", "two", "0003", "a\tb"}})
+			})
+		})
+	})
+}
+
+func TestUnitEncodeWithUnknownTags(t *testing.T) {
+	Convey("Given a tag map", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		encoder := NewEncoder(&bytes.Buffer{}, tagMap)
+
+		Convey("When encoding an unknown tag name", func() {
+			_, err := encoder.EncodeToString([]Field{{Tag: "eleven", Value: "x"}})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, fmt.Errorf("unknown tag name: %s", "eleven"))
+			})
+		})
+
+		Convey("When encoding an unknown tag id", func() {
+			_, err := encoder.EncodeToString([]Field{{Tag: "11", Value: "x"}})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, fmt.Errorf("unknown id: %s", "0011"))
+			})
+		})
+
+		Convey("When encoding an out of range tag id", func() {
+			_, err := encoder.EncodeToString([]Field{{Tag: "10000", Value: "x"}})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, fmt.Errorf("tag id out of range: %s", "10000"))
+			})
+		})
+
+		Convey("When encoding no fields", func() {
+			_, err := encoder.EncodeToString(nil)
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, errors.New("no fields to encode"))
+			})
+		})
+	})
+}
+
+func TestUnitEncodeWithValueTooLong(t *testing.T) {
+	Convey("Given a tag map and a value longer than the length field allows", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		value := strings.Repeat("x", 10000)
+
+		Convey("When encoding the value", func() {
+			data, err := NewEncoder(&bytes.Buffer{}, tagMap).EncodeToString([]Field{{Tag: "one", Value: value}})
+
+			Convey("The data should be empty", func() {
+				So(data, ShouldBeEmpty)
+			})
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, fmt.Errorf("value too long for tag with id %s: %d bytes", "0001", 10000))
+			})
+		})
+	})
+}
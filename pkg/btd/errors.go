@@ -0,0 +1,92 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+// ParseErrorKind identifies the category of problem found while parsing a tag.
+type ParseErrorKind int
+
+const (
+	TruncatedID ParseErrorKind = iota
+	NonNumericID
+	TruncatedLength
+	NonNumericLength
+	TruncatedData
+	UnknownID
+	MissingSeparator
+)
+
+var parseErrorKindNames = map[ParseErrorKind]string{
+	TruncatedID:      "truncated-id",
+	NonNumericID:     "non-numeric-id",
+	TruncatedLength:  "truncated-length",
+	NonNumericLength: "non-numeric-length",
+	TruncatedData:    "truncated-data",
+	UnknownID:        "unknown-id",
+	MissingSeparator: "missing-separator",
+}
+
+func (k ParseErrorKind) String() string {
+	if name, ok := parseErrorKindNames[k]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// ParseError describes a problem found while parsing a tag. Use errors.As to
+// retrieve it from errors returned by Decoder and TagMap parsing methods.
+// Offsets and sizes are counted in characters if the decoder counts
+// characters (see Decoder.CharacterOffsets), and otherwise in bytes.
+type ParseError struct {
+	Kind        ParseErrorKind
+	Offset      int    // offset of the tag within its transaction
+	FieldOffset int    // offset of the field at fault within its transaction
+	ID          string // tag id, or as much of it as could be read
+	Expected    int    // size the field should have
+	Available   int    // size available to read for the field
+	Err         error  // underlying error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Truncated reports whether the error was caused by the data ending before
+// a complete field could be read.
+func (e *ParseError) Truncated() bool {
+	return e.Kind == TruncatedID || e.Kind == TruncatedLength || e.Kind == TruncatedData
+}
+
+// Segment returns the start and end offsets of the field at fault.
+func (e *ParseError) Segment() (start, end int) {
+	return e.FieldOffset, e.FieldOffset + e.Available
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// recoverable reports whether parsing can continue with the next tag of the
+// same transaction after the error.
+func (e *ParseError) recoverable() bool {
+	return e.Kind == UnknownID
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ExtractGroup is the name of the regular expression group matching the
+// business transaction data within a log line.
+const ExtractGroup = "btd"
+
+// Extractor pulls business transaction data out of log lines using a regular
+// expression. The data is matched by a group named btd; any other named
+// groups, such as a timestamp or request id, are kept as metadata.
+type Extractor struct {
+	pattern *regexp.Regexp
+}
+
+// LogField is a named value captured from the log line a transaction was
+// extracted from.
+type LogField struct {
+	Name  string
+	Value string
+}
+
+// NewExtractor returns an Extractor using the regular expression pattern,
+// which must contain a group named btd.
+func NewExtractor(pattern string) (*Extractor, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log pattern: %w", err)
+	}
+
+	if re.SubexpIndex(ExtractGroup) < 0 {
+		return nil, errors.New("log pattern must contain a group named " + ExtractGroup + ": (?P<" + ExtractGroup + ">...)")
+	}
+
+	return &Extractor{pattern: re}, nil
+}
+
+// Extract returns the business transaction data within line and the values
+// of the pattern's other named groups, in the order they appear in the
+// pattern. It reports false if the line does not match.
+func (e *Extractor) Extract(line string) (string, []LogField, bool) {
+	match := e.pattern.FindStringSubmatch(line)
+	if match == nil {
+		return "", nil, false
+	}
+
+	var (
+		data   string
+		fields []LogField
+	)
+
+	for i, name := range e.pattern.SubexpNames() {
+		switch name {
+		case "":
+		case ExtractGroup:
+			data = match[i]
+		default:
+			fields = append(fields, LogField{Name: name, Value: match[i]})
+		}
+	}
+
+	return data, fields, true
+}
+
+// logReader replaces each line read from r with the business transaction
+// data extracted from it, or an empty line if it does not match, so that
+// line numbers are unchanged. Lines also end at U+0085 (NEL) if nel is set.
+// The metadata of each extracted line is kept until it is requested.
+type logReader struct {
+	r         *bufio.Reader
+	extractor *Extractor
+	nel       bool
+	buf       bytes.Buffer
+	line      int
+	metadata  map[int][]LogField
+}
+
+func newLogReader(r io.Reader, extractor *Extractor, nel bool) *logReader {
+	return &logReader{
+		r:         bufio.NewReader(r),
+		extractor: extractor,
+		nel:       nel,
+		metadata:  make(map[int][]LogField),
+	}
+}
+
+func (l *logReader) Read(p []byte) (int, error) {
+	for l.buf.Len() == 0 {
+		line, err := l.readLine()
+		if len(line) == 0 && err != nil {
+			return 0, err
+		}
+
+		l.line++
+
+		line = bytes.TrimSuffix(bytes.TrimRight(line, "\r\n"), []byte("\u0085"))
+
+		if data, fields, ok := l.extractor.Extract(string(line)); ok {
+			l.buf.WriteString(data)
+			if len(fields) > 0 {
+				l.metadata[l.line] = fields
+			}
+		}
+
+		l.buf.WriteByte('\n')
+	}
+
+	return l.buf.Read(p)
+}
+
+// readLine reads the next line, including its line ending.
+func (l *logReader) readLine() ([]byte, error) {
+	if !l.nel {
+		return l.r.ReadBytes('\n')
+	}
+
+	var line []byte
+
+	for {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return line, err
+		}
+
+		line = append(line, b)
+
+		if b == '\n' || bytes.HasSuffix(line, []byte("\u0085")) {
+			return line, nil
+		}
+	}
+}
+
+// takeMetadata returns the metadata of the given line, discarding the
+// metadata of that line and any before it.
+func (l *logReader) takeMetadata(line int) []LogField {
+	fields := l.metadata[line]
+
+	for n := range l.metadata {
+		if n <= line {
+			delete(l.metadata, n)
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,167 @@
+package btd
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const testLogPattern = `^(?P<timestamp>\S+) \[(?P<request_id>[^\]]+)\] BTD: (?P<btd>\S+)$`
+
+func TestUnitNewExtractor(t *testing.T) {
+	Convey("Given a pattern without a btd group", t, func() {
+
+		Convey("When creating an extractor", func() {
+			extractor, err := NewExtractor(`^(?P<timestamp>\S+) (\S+)$`)
+
+			Convey("The extractor should be nil", func() {
+				So(extractor, ShouldBeNil)
+			})
+
+			Convey("The error should explain the group required", func() {
+				So(err.Error(), ShouldEqual, "log pattern must contain a group named btd: (?P<btd>...)")
+			})
+		})
+	})
+
+	Convey("Given an invalid pattern", t, func() {
+
+		Convey("When creating an extractor", func() {
+			_, err := NewExtractor(`(?P<btd>`)
+
+			Convey("The error should identify the pattern as invalid", func() {
+				So(err.Error(), ShouldStartWith, "invalid log pattern: ")
+			})
+		})
+	})
+}
+
+func TestUnitExtract(t *testing.T) {
+	Convey("Given an extractor", t, func() {
+
+		extractor, err := NewExtractor(testLogPattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When extracting from a matching line", func() {
+			data, fields, ok := extractor.Extract("2026-10-17T09:00:00Z [req-1] BTD: 00010001a")
+
+			Convey("The data and metadata should be returned in pattern order", func() {
+				So(ok, ShouldBeTrue)
+				So(data, ShouldEqual, "00010001a")
+				So(fields, ShouldResemble, []LogField{
+					{Name: "timestamp", Value: "2026-10-17T09:00:00Z"},
+					{Name: "request_id", Value: "req-1"},
+				})
+			})
+		})
+
+		Convey("When extracting from a line that does not match", func() {
+			_, _, ok := extractor.Extract("2026-10-17T09:00:00Z [req-1] starting up")
+
+			Convey("No data should be returned", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestUnitDecodeWithExtractor(t *testing.T) {
+	Convey("Given a log containing transactions among other lines", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		extractor, err := NewExtractor(testLogPattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		log := strings.Join([]string{
+			"2026-10-17T09:00:00Z [req-1] starting up",
+			"2026-10-17T09:00:01Z [req-1] BTD: 00010001a",
+			"",
+			"2026-10-17T09:00:02Z [req-2] BTD: 00020001b00030001c",
+			"2026-10-17T09:00:03Z [req-2] done",
+		}, "\r\n")
+
+		d := NewDecoder(strings.NewReader(log), tagMap)
+		d.Extractor = extractor
+
+		Convey("When decoding each transaction", func() {
+			first, err := d.Decode()
+			So(err, ShouldBeNil)
+
+			second, err := d.Decode()
+			So(err, ShouldBeNil)
+
+			_, err = d.Decode()
+
+			Convey("Only the matching lines should be decoded", func() {
+				So(first.TagData(), ShouldResemble, TagData{{"0001", "one", "0001", "a"}})
+				So(second.Len(), ShouldEqual, 2)
+				So(err, ShouldEqual, io.EOF)
+			})
+
+			Convey("The line numbers should be those of the log", func() {
+				So(first.Line, ShouldEqual, 2)
+				So(second.Line, ShouldEqual, 4)
+			})
+
+			Convey("Each transaction should carry the metadata of its line", func() {
+				So(first.Metadata, ShouldResemble, []LogField{
+					{Name: "timestamp", Value: "2026-10-17T09:00:01Z"},
+					{Name: "request_id", Value: "req-1"},
+				})
+				So(second.Metadata[1].Value, ShouldEqual, "req-2")
+			})
+		})
+	})
+}
+
+func TestUnitDecodeWithExtractorAndCharset(t *testing.T) {
+	Convey("Given an EBCDIC log using NEL line endings", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		extractor, err := NewExtractor(testLogPattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		log, err := charmap.CodePage037.NewEncoder().String(strings.Join([]string{
+			"2026-10-17T09:00:00Z [req-é] starting up",
+			"2026-10-17T09:00:01Z [req-é] BTD: 00010005Caffé",
+		}, "\u0085"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := NewDecoder(strings.NewReader(log), tagMap)
+		d.Charset = CP037
+		d.Extractor = extractor
+
+		Convey("When decoding the log", func() {
+			tx, err := d.Decode()
+
+			Convey("The transaction should be extracted from the decoded line", func() {
+				So(err, ShouldBeNil)
+				So(tx.Line, ShouldEqual, 2)
+				So(tx.TagData(), ShouldResemble, TagData{{"0001", "one", "0005", "Caffé"}})
+			})
+
+			Convey("The metadata should be decoded", func() {
+				So(tx.Metadata[1], ShouldResemble, LogField{Name: "request_id", Value: "req-é"})
+			})
+		})
+	})
+}
@@ -0,0 +1,464 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// TagMapFormat is a file format in which a tag map may be written.
+type TagMapFormat int
+
+const (
+	// FormatAuto detects the format of a tag map file from its extension.
+	FormatAuto TagMapFormat = iota
+	// FormatDat is the whitespace-separated tagmap.dat format.
+	FormatDat
+	FormatJSON
+	FormatYAML
+	FormatTOML
+	// FormatCSV is a CSV file with a header row naming its columns; groups
+	// cannot be declared in this format.
+	FormatCSV
+)
+
+var tagMapFormatNames = []string{"auto", "dat", "json", "yaml", "toml", "csv"}
+
+func (f TagMapFormat) String() string {
+	if int(f) < len(tagMapFormatNames) {
+		return tagMapFormatNames[f]
+	}
+
+	return "unknown"
+}
+
+// ParseTagMapFormat returns the tag map format with the given name.
+func ParseTagMapFormat(name string) (TagMapFormat, error) {
+	if name == "yml" {
+		return FormatYAML, nil
+	}
+
+	for i, n := range tagMapFormatNames {
+		if n == name {
+			return TagMapFormat(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("tag map format must be one of %v: %s", tagMapFormatNames, name)
+}
+
+// DetectTagMapFormat returns the format of the tag map file at path, based on
+// its extension. Files without a recognised extension are assumed to be in
+// the tagmap.dat format.
+func DetectTagMapFormat(path string) TagMapFormat {
+	format, err := ParseTagMapFormat(strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")))
+	if err != nil || format == FormatAuto {
+		return FormatDat
+	}
+
+	return format
+}
+
+// tagMapDocument is the structure of a tag map in the JSON, YAML and TOML
+// formats.
+type tagMapDocument struct {
+	Tags   []tagEntry   `json:"tags" yaml:"tags" toml:"tags"`
+	Groups []groupEntry `json:"groups,omitempty" yaml:"groups,omitempty" toml:"groups,omitempty"`
+}
+
+type tagEntry struct {
+	ID          documentID `json:"id" yaml:"id" toml:"id"`
+	Name        string     `json:"name" yaml:"name" toml:"name"`
+	Type        string     `json:"type,omitempty" yaml:"type,omitempty" toml:"type,omitempty"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Aliases     []string   `json:"aliases,omitempty" yaml:"aliases,omitempty" toml:"aliases,omitempty"`
+	Layout      string     `json:"layout,omitempty" yaml:"layout,omitempty" toml:"layout,omitempty"`
+	Codes       []Code     `json:"codes,omitempty" yaml:"codes,omitempty" toml:"codes,omitempty"`
+
+	line int // line number in YAML files
+}
+
+type groupEntry struct {
+	Name    string       `json:"name" yaml:"name" toml:"name"`
+	Start   documentID   `json:"start" yaml:"start" toml:"start"`
+	End     documentID   `json:"end,omitempty" yaml:"end,omitempty" toml:"end,omitempty"`
+	Members []documentID `json:"members,omitempty" yaml:"members,omitempty" toml:"members,omitempty"`
+
+	line int
+}
+
+// documentID is a tag id that may be written as either a string or a number,
+// retaining any leading zeros as written.
+type documentID string
+
+func (id *documentID) UnmarshalJSON(data []byte) error {
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		*id = documentID(s)
+		return nil
+	}
+
+	*id = documentID(data)
+	return nil
+}
+
+func (id *documentID) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: id must be a string or number", node.Line)
+	}
+
+	*id = documentID(node.Value)
+	return nil
+}
+
+func (e *tagEntry) UnmarshalYAML(node *yaml.Node) error {
+	type plain tagEntry
+	e.line = node.Line
+	return node.Decode((*plain)(e))
+}
+
+func (e *groupEntry) UnmarshalYAML(node *yaml.Node) error {
+	type plain groupEntry
+	e.line = node.Line
+	return node.Decode((*plain)(e))
+}
+
+// decodeTagMap parses a tag map file in the given format. The line number of
+// each definition is its position within the file for formats that do not
+// report line numbers.
+func decodeTagMap(r io.Reader, path string, format TagMapFormat) (*tagMapFile, error) {
+	if format == FormatAuto {
+		format = DetectTagMapFormat(path)
+	}
+
+	var doc tagMapDocument
+
+	switch format {
+	case FormatDat:
+		return parseTagMap(r, path)
+	case FormatCSV:
+		return decodeCSVTagMap(r, path)
+	case FormatJSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Err: err}
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(r)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&doc); err != nil && err != io.EOF {
+			return nil, &TagMapSyntaxError{Path: path, Err: err}
+		}
+	case FormatTOML:
+		// TOML integers cannot have leading zeros, so ids are decoded
+		// generically and converted to JSON to be read as strings or numbers
+		var raw map[string]any
+		if err := toml.NewDecoder(r).Decode(&raw); err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Err: err}
+		}
+
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Err: err}
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Err: err}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tag map format: %v", format)
+	}
+
+	return doc.file(path)
+}
+
+// file validates the definitions in the document, returning the tag map they
+// describe.
+func (doc *tagMapDocument) file(path string) (*tagMapFile, error) {
+	file := &tagMapFile{}
+
+	for i, entry := range doc.Tags {
+		line := cmp.Or(entry.line, i+1)
+
+		def, err := parseTagDef(entry.fields())
+		if err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Line: line, Err: err}
+		}
+
+		def.Line = line
+		file.tags = append(file.tags, def)
+	}
+
+	for i, entry := range doc.Groups {
+		def, err := parseGroupDef(entry.fields())
+		if err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Line: cmp.Or(entry.line, i+1), Err: err}
+		}
+
+		file.groups = append(file.groups, def)
+	}
+
+	return file, nil
+}
+
+// fields returns the entry in the form of the fields of a tagmap.dat line.
+func (e tagEntry) fields() []string {
+	if len(e.Name) == 0 {
+		return []string{string(e.ID)}
+	}
+
+	fields := []string{string(e.ID), e.Name}
+
+	if len(e.Type) > 0 {
+		fields = append(fields, "type="+e.Type)
+	}
+	if len(e.Description) > 0 {
+		fields = append(fields, "desc="+e.Description)
+	}
+	if len(e.Aliases) > 0 {
+		fields = append(fields, "alias="+strings.Join(e.Aliases, ","))
+	}
+	if len(e.Layout) > 0 {
+		fields = append(fields, "layout="+e.Layout)
+	}
+	for _, code := range e.Codes {
+		fields = append(fields, "code="+code.Value+":"+code.Label)
+	}
+
+	return fields
+}
+
+// fields returns the entry in the form of the fields of a tagmap.dat group
+// directive.
+func (e groupEntry) fields() []string {
+	if len(e.End) > 0 {
+		return []string{"group", e.Name, string(e.Start), string(e.End)}
+	}
+
+	fields := []string{"repeat", e.Name, string(e.Start)}
+	for _, id := range e.Members {
+		fields = append(fields, string(id))
+	}
+
+	return fields
+}
+
+// csvColumns are the columns of a CSV tag map, of which only id and name are
+// required. Aliases are separated by semicolons, as are the codes of an
+// enumerated tag, each written as value:label.
+var csvColumns = []string{"id", "name", "type", "description", "aliases", "layout", "codes"}
+
+// decodeCSVTagMap parses a CSV tag map file, whose first row names its
+// columns.
+func decodeCSVTagMap(r io.Reader, path string) (*tagMapFile, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &tagMapFile{}, nil
+	}
+	if err != nil {
+		return nil, &TagMapSyntaxError{Path: path, Line: 1, Err: err}
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvColumns, name) {
+			return nil, &TagMapSyntaxError{Path: path, Line: 1, Err: fmt.Errorf("unknown column: %s", name)}
+		}
+		columns[name] = i
+	}
+
+	for _, name := range csvColumns[:2] {
+		if _, ok := columns[name]; !ok {
+			return nil, &TagMapSyntaxError{Path: path, Line: 1, Err: fmt.Errorf("missing column: %s", name)}
+		}
+	}
+
+	file := &tagMapFile{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return file, nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.Line
+			}
+			return nil, &TagMapSyntaxError{Path: path, Line: line, Err: err}
+		}
+
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := tagEntry{ID: documentID(column("id")), Name: column("name"), Type: column("type"), Description: column("description"), Layout: column("layout")}
+		if aliases := column("aliases"); len(aliases) > 0 {
+			for _, alias := range strings.Split(aliases, ";") {
+				entry.Aliases = append(entry.Aliases, strings.TrimSpace(alias))
+			}
+		}
+
+		fields := entry.fields()
+		if codes := column("codes"); len(codes) > 0 {
+			for _, code := range strings.Split(codes, ";") {
+				fields = append(fields, "code="+strings.TrimSpace(code))
+			}
+		}
+
+		def, err := parseTagDef(fields)
+		if err != nil {
+			return nil, &TagMapSyntaxError{Path: path, Line: line, Err: err}
+		}
+
+		def.Line = line
+		file.tags = append(file.tags, def)
+	}
+}
+
+// WriteTagMap writes the tag definitions and groups of tagMap to w in the
+// given format. Groups cannot be written in the CSV format.
+func WriteTagMap(w io.Writer, tagMap TagMap, format TagMapFormat) error {
+	doc := tagMapDocument{}
+
+	for _, def := range tagMap.Tags() {
+		doc.Tags = append(doc.Tags, tagEntry{
+			ID:          documentID(def.ID),
+			Name:        def.Name,
+			Type:        def.Type,
+			Description: def.Description,
+			Aliases:     def.Aliases,
+			Layout:      def.Layout,
+			Codes:       def.Codes,
+		})
+	}
+
+	for _, def := range tagMap.Groups() {
+		entry := groupEntry{Name: def.Name, Start: documentID(def.Start), End: documentID(def.End)}
+		for _, id := range def.Members {
+			entry.Members = append(entry.Members, documentID(id))
+		}
+		doc.Groups = append(doc.Groups, entry)
+	}
+
+	switch format {
+	case FormatDat:
+		return writeDatTagMap(w, &doc)
+	case FormatCSV:
+		return writeCSVTagMap(w, &doc)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(doc)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatTOML:
+		return toml.NewEncoder(w).Encode(doc)
+	default:
+		return fmt.Errorf("unsupported tag map format: %v", format)
+	}
+}
+
+func writeDatTagMap(w io.Writer, doc *tagMapDocument) error {
+	for _, entry := range doc.Tags {
+		fields := entry.fields()
+		for i, field := range fields[2:] {
+			fields[i+2] = quoteAttribute(field)
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(fields, " ")); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range doc.Groups {
+		if _, err := fmt.Fprintln(w, strings.Join(entry.fields(), " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quoteAttribute quotes the value of a key=value attribute if it contains
+// characters that would otherwise end it.
+func quoteAttribute(attr string) string {
+	key, value, _ := strings.Cut(attr, "=")
+
+	if !strings.ContainsAny(value, " \t\"#\\") {
+		return attr
+	}
+
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return key + `="` + value + `"`
+}
+
+func writeCSVTagMap(w io.Writer, doc *tagMapDocument) error {
+	if len(doc.Groups) > 0 {
+		return errors.New("groups cannot be written in csv format")
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(csvColumns)
+
+	for _, entry := range doc.Tags {
+		var codes []string
+		for _, code := range entry.Codes {
+			codes = append(codes, code.Value+":"+code.Label)
+		}
+
+		writer.Write([]string{string(entry.ID), entry.Name, entry.Type, entry.Description, strings.Join(entry.Aliases, ";"), entry.Layout, strings.Join(codes, ";")})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,192 @@
+package btd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// withoutPositions returns tags with their line numbers and sources cleared,
+// for comparing definitions loaded from different files.
+func withoutPositions(tags []TagDef) []TagDef {
+	out := make([]TagDef, len(tags))
+	for i, tag := range tags {
+		tag.Line, tag.Source = 0, ""
+		out[i] = tag
+	}
+	return out
+}
+
+func TestUnitDetectTagMapFormat(t *testing.T) {
+	Convey("Given tag map file paths with different extensions", t, func() {
+
+		tests := map[string]TagMapFormat{
+			"tagmap.dat":  FormatDat,
+			"tagmap":      FormatDat,
+			"tagmap.JSON": FormatJSON,
+			"tagmap.yml":  FormatYAML,
+			"tagmap.yaml": FormatYAML,
+			"tagmap.toml": FormatTOML,
+			"tagmap.csv":  FormatCSV,
+			"tagmap.auto": FormatDat,
+		}
+
+		for path, format := range tests {
+			Convey("When detecting the format of "+path, func() {
+
+				Convey("The format should be "+format.String(), func() {
+					So(DetectTagMapFormat(path), ShouldEqual, format)
+				})
+			})
+		}
+	})
+}
+
+func TestUnitLoadTagMapInEachFormat(t *testing.T) {
+	Convey("Given equivalent tag maps in each structured format", t, func() {
+
+		expected := []TagDef{
+			{ID: "0001", Name: "company_number", Aliases: []string{"crn"}, Type: "string", Description: "Company registration number"},
+			{ID: "10", Name: "address_line_2"},
+		}
+
+		for _, name := range []string{"tagmap.json", "tagmap.yaml", "tagmap.toml", "tagmap.csv"} {
+			Convey("When loading "+name, func() {
+				tagMap, err := LoadTagMap("testdata/formats/" + name)
+
+				Convey("The error should be nil", func() {
+					So(err, ShouldBeNil)
+				})
+
+				Convey("The tag definitions should be loaded", func() {
+					So(withoutPositions(tagMap.Tags()), ShouldResemble, expected)
+				})
+
+				Convey("Ids should be matched numerically", func() {
+					id, err := tagMap.GetTagName("0010")
+					So(id, ShouldEqual, "address_line_2")
+					So(err, ShouldBeNil)
+				})
+
+				if name != "tagmap.csv" {
+					Convey("The groups should be loaded", func() {
+						So(tagMap.Groups(), ShouldResemble, []GroupDef{{Name: "address", Start: "0010", Members: []string{"0011"}}})
+					})
+				}
+			})
+		}
+	})
+}
+
+func TestUnitLoadTagMapKeepsLeadingZerosInYAML(t *testing.T) {
+	Convey("Given a YAML tag map with an unquoted id containing leading zeros", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/formats/tagmap.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When retrieving the tag definitions", func() {
+			tags := tagMap.Tags()
+
+			Convey("The id should be kept as written rather than read as octal", func() {
+				So(tags[0].ID, ShouldEqual, "0001")
+				So(tags[0].Line, ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestUnitDecodeTagMapWithInvalidEntries(t *testing.T) {
+	Convey("Given structured tag maps containing invalid entries", t, func() {
+
+		tests := []struct {
+			format TagMapFormat
+			input  string
+			err    string
+		}{
+			{FormatJSON, `{"tags": [{"id": "0001", "name": "2nd"}]}`, `tagmap:1: invalid name for id 0001: "2nd"`},
+			{FormatJSON, `{"tags": [], "extra": 1}`, `tagmap: json: unknown field "extra"`},
+			{FormatYAML, "tags:\n  - id: 0001\n    name: one\n  - id: x\n    name: two\n", `tagmap:4: expected numeric id or directive: found "x"`},
+			{FormatCSV, "id,colour\n", "tagmap:1: unknown column: colour"},
+			{FormatCSV, "id,name\n0001,one\n0002\n", "tagmap:3: missing name for id: 0002"},
+			{FormatCSV, "id,name\n0001,one\n0003,3rd\n", `tagmap:3: invalid name for id 0003: "3rd"`},
+		}
+
+		for _, test := range tests {
+			Convey("When decoding "+test.format.String()+" "+strings.ReplaceAll(test.input, "\n", " "), func() {
+				file, err := decodeTagMap(strings.NewReader(test.input), "tagmap", test.format)
+
+				Convey("The file should be nil", func() {
+					So(file, ShouldBeNil)
+				})
+
+				Convey("The error should identify the problem", func() {
+					var syntaxErr *TagMapSyntaxError
+					So(errors.As(err, &syntaxErr), ShouldBeTrue)
+					So(err.Error(), ShouldEqual, test.err)
+				})
+			})
+		}
+	})
+}
+
+func TestUnitWriteTagMap(t *testing.T) {
+	Convey("Given a tag map with attributes and groups", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_rich.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, format := range []TagMapFormat{FormatDat, FormatJSON, FormatYAML, FormatTOML} {
+			Convey("When writing and reloading the tag map in "+format.String()+" format", func() {
+				var buf bytes.Buffer
+				err := WriteTagMap(&buf, tagMap, format)
+				So(err, ShouldBeNil)
+
+				file, err := decodeTagMap(&buf, "tagmap", format)
+
+				Convey("The tag definitions and groups should be unchanged", func() {
+					So(err, ShouldBeNil)
+					So(withoutPositions(file.tags), ShouldResemble, withoutPositions(tagMap.Tags()))
+					So(file.groups, ShouldResemble, tagMap.Groups())
+				})
+			})
+		}
+
+		Convey("When writing the tag map in csv format", func() {
+			err := WriteTagMap(&bytes.Buffer{}, tagMap, FormatCSV)
+
+			Convey("The error should explain that groups cannot be written", func() {
+				So(err.Error(), ShouldEqual, "groups cannot be written in csv format")
+			})
+		})
+	})
+
+	Convey("Given a tag map with typed tags", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_typed.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, format := range []TagMapFormat{FormatDat, FormatJSON, FormatYAML, FormatTOML, FormatCSV} {
+			Convey("When writing and reloading the tag map in "+format.String()+" format", func() {
+				var buf bytes.Buffer
+				err := WriteTagMap(&buf, tagMap, format)
+				So(err, ShouldBeNil)
+
+				file, err := decodeTagMap(&buf, "tagmap", format)
+
+				Convey("The layouts and codes should be unchanged", func() {
+					So(err, ShouldBeNil)
+					So(withoutPositions(file.tags), ShouldResemble, withoutPositions(tagMap.Tags()))
+				})
+			})
+		}
+	})
+}
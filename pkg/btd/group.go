@@ -0,0 +1,226 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GroupDef describes a group of related tags declared in the tag map. A
+// block group is delimited by its start and end tags; a repeating group
+// begins with its start tag and continues for as long as the tags that
+// follow are members of the group.
+type GroupDef struct {
+	Name    string
+	Start   string   // id of the tag that begins the group
+	End     string   // id of the tag that ends a block group; empty for a repeating group
+	Members []string // ids of the tags following the start tag in a repeating group
+}
+
+// Repeating reports whether the group is a repeating group rather than a
+// block group.
+func (g *GroupDef) Repeating() bool {
+	return len(g.End) == 0
+}
+
+// parseGroupDef parses a group directive from the tag map, in one of the
+// forms:
+//
+//	group <name> <start-id> <end-id>
+//	repeat <name> <start-id> [<member-id>...]
+func parseGroupDef(fields []string) (GroupDef, error) {
+	if len(fields) < 3 {
+		return GroupDef{}, fmt.Errorf("%s directive requires a name and start id", fields[0])
+	}
+
+	if !isIdentifier(fields[1]) {
+		return GroupDef{}, fmt.Errorf("invalid name in %s directive: %q", fields[0], fields[1])
+	}
+
+	def := GroupDef{Name: fields[1]}
+	ids := fields[2:]
+
+	for _, id := range ids {
+		if _, err := parseUIntValue(id); err != nil {
+			return GroupDef{}, fmt.Errorf("found non-numeric id in %s directive: %s", fields[0], id)
+		}
+	}
+
+	switch fields[0] {
+	case "group":
+		if len(ids) != 2 {
+			return GroupDef{}, fmt.Errorf("group directive requires a start and end id: %s", def.Name)
+		}
+		def.Start, def.End = ids[0], ids[1]
+	case "repeat":
+		def.Start = ids[0]
+		if len(ids) > 1 {
+			def.Members = ids[1:]
+		}
+	}
+
+	return def, nil
+}
+
+// isGroupDirective reports whether keyword introduces a group directive.
+func isGroupDirective(keyword string) bool {
+	return keyword == "group" || keyword == "repeat"
+}
+
+// Group is a section of a transaction. The root group of a transaction has
+// no name and contains every tag, either directly or within a nested group.
+type Group struct {
+	Name       string
+	Occurrence int // 1-based occurrence of the group within its parent
+	Nodes      []Node
+
+	def *GroupDef
+}
+
+// Node is an entry within a group: either a tag or a nested group.
+type Node struct {
+	Tag   *Tag
+	Group *Group
+}
+
+// HasGroups reports whether the group contains any nested groups.
+func (g *Group) HasGroups() bool {
+	return slices.ContainsFunc(g.Nodes, func(n Node) bool { return n.Group != nil })
+}
+
+// Groups returns the nested groups with the given name, in order.
+func (g *Group) Groups(name string) []*Group {
+	var groups []*Group
+
+	for _, node := range g.Nodes {
+		if node.Group != nil && node.Group.Name == name {
+			groups = append(groups, node.Group)
+		}
+	}
+
+	return groups
+}
+
+func (g *Group) String() string {
+	return g.Name + " #" + strconv.Itoa(g.Occurrence)
+}
+
+func (g *Group) add(node Node) {
+	g.Nodes = append(g.Nodes, node)
+}
+
+// open adds a new nested group for def and returns it.
+func (g *Group) open(def *GroupDef) *Group {
+	group := &Group{Name: def.Name, Occurrence: len(g.Groups(def.Name)) + 1, def: def}
+	g.add(Node{Group: group})
+
+	return group
+}
+
+// accepts reports whether a tag with the given id belongs within the group.
+func (g *Group) accepts(id string) bool {
+	return g.def == nil || !g.def.Repeating() || slices.ContainsFunc(g.def.Members, func(member string) bool {
+		return normaliseID(member) == id
+	})
+}
+
+// buildGroups arranges tags into a tree of groups according to defs.
+func buildGroups(tags []Tag, defs []GroupDef) *Group {
+	root := &Group{}
+	stack := []*Group{root}
+
+	top := func() *Group { return stack[len(stack)-1] }
+
+	// closeGroup pops the stack up to and including the innermost open
+	// group matching def, returning the group closed, if any.
+	closeGroup := func(match func(*GroupDef) bool) *Group {
+		for i := len(stack) - 1; i > 0; i-- {
+			if group := stack[i]; match(group.def) {
+				stack = stack[:i]
+				return group
+			}
+		}
+		return nil
+	}
+
+	for i := range tags {
+		tag := &tags[i]
+		id := tag.key()
+
+		// the end tag of a block belongs within the block it closes
+		if block := closeGroup(func(def *GroupDef) bool { return !def.Repeating() && normaliseID(def.End) == id }); block != nil {
+			block.add(Node{Tag: tag})
+			continue
+		}
+
+		if def := findGroupDef(defs, id); def != nil {
+			if def.Repeating() {
+				closeGroup(func(d *GroupDef) bool { return d == def })
+			}
+			for !top().accepts(id) {
+				stack = stack[:len(stack)-1]
+			}
+
+			group := top().open(def)
+			group.add(Node{Tag: tag})
+			stack = append(stack, group)
+			continue
+		}
+
+		for !top().accepts(id) {
+			stack = stack[:len(stack)-1]
+		}
+
+		top().add(Node{Tag: tag})
+	}
+
+	return root
+}
+
+func findGroupDef(defs []GroupDef, id string) *GroupDef {
+	for i := range defs {
+		if normaliseID(defs[i].Start) == id {
+			return &defs[i]
+		}
+	}
+
+	return nil
+}
+
+// key returns the normalised id of the tag, used to match it against the
+// tag map and group definitions. The id is parsed using the tag's dialect, so
+// that padding characters other than '0' are removed.
+func (t *Tag) key() string {
+	dialect := t.Dialect
+	if dialect == nil {
+		dialect = Standard
+	}
+
+	if id, err := dialect.parseID(t.ID); err == nil {
+		return strconv.FormatUint(id, 10)
+	}
+
+	return normaliseID(strings.TrimSpace(t.ID))
+}
@@ -0,0 +1,176 @@
+package btd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// groupNodes returns the names of the tags and groups directly within group.
+func groupNodes(group *Group) []string {
+	var names []string
+
+	for _, node := range group.Nodes {
+		if node.Group != nil {
+			names = append(names, node.Group.String())
+		} else {
+			names = append(names, node.Tag.Name)
+		}
+	}
+
+	return names
+}
+
+func TestUnitLoadTagMapWithGroups(t *testing.T) {
+	Convey("Given a tag map declaring a block group and a repeating group", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_groups.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When retrieving the group definitions", func() {
+			groups := tagMap.Groups()
+
+			Convey("The groups should be correct", func() {
+				So(groups, ShouldResemble, []GroupDef{
+					{Name: "block", Start: "0002", End: "0005"},
+					{Name: "officer", Start: "0003", Members: []string{"0004"}},
+				})
+			})
+
+			Convey("Only the second group should be repeating", func() {
+				So(groups[0].Repeating(), ShouldBeFalse)
+				So(groups[1].Repeating(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When retrieving the name of a tag", func() {
+			name, err := tagMap.GetTagName("10")
+
+			Convey("The tag mappings should be unaffected", func() {
+				So(name, ShouldEqual, "ten")
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestUnitLoadTagMapWithMalformedGroup(t *testing.T) {
+	Convey("Given a tag map with a group directive missing its end id", t, func() {
+
+		path := filepath.Join(t.TempDir(), "tagmap.dat")
+		if err := os.WriteFile(path, []byte("0001 one\ngroup block 0001\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When loading the tag map", func() {
+			tagMap, err := LoadTagMap(path)
+
+			Convey("The tag map should be nil", func() {
+				So(tagMap, ShouldBeNil)
+			})
+
+			Convey("The error should identify the line", func() {
+				So(err.Error(), ShouldEqual, path+":2: group directive requires a start and end id: block")
+			})
+		})
+	})
+}
+
+func TestUnitParseTransactionWithGroups(t *testing.T) {
+	Convey("Given a tag map declaring groups and a transaction repeating a group within a block", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_groups.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data := "00010001a00020001b00030001c00040001d00030001e00040001f00050001g00060001h"
+
+		Convey("When parsing the transaction", func() {
+			tx, err := tagMap.ParseTransaction(data)
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The flattened tag data should be unaffected", func() {
+				So(tx.Len(), ShouldEqual, 8)
+			})
+
+			Convey("The root should contain the block between the ungrouped tags", func() {
+				So(tx.Root.HasGroups(), ShouldBeTrue)
+				So(groupNodes(tx.Root), ShouldResemble, []string{"one", "block #1", "six"})
+			})
+
+			Convey("The block should contain each occurrence of the repeating group and its end tag", func() {
+				block := tx.Root.Groups("block")[0]
+				So(groupNodes(block), ShouldResemble, []string{"two", "officer #1", "officer #2", "five"})
+			})
+
+			Convey("Each occurrence of the repeating group should contain its own tags", func() {
+				officers := tx.Root.Groups("block")[0].Groups("officer")
+				So(officers, ShouldHaveLength, 2)
+				So(officers[0].Nodes[0].Tag.Value, ShouldEqual, "c")
+				So(officers[0].Nodes[1].Tag.Value, ShouldEqual, "d")
+				So(officers[1].Nodes[0].Tag.Value, ShouldEqual, "e")
+				So(officers[1].Nodes[1].Tag.Value, ShouldEqual, "f")
+			})
+		})
+	})
+
+	Convey("Given a tag map declaring groups and data using a dialect padded with underscores", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_groups.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoder := NewDecoder(strings.NewReader("___1___1a___2___1b___3___1c___4___1d___5___1e"), tagMap)
+		decoder.Dialect = &Dialect{Name: "underscore", IDWidth: 4, LengthWidth: 4, LengthBase: 10, Padding: '_'}
+
+		Convey("When decoding the transaction", func() {
+			tx, err := decoder.Decode()
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The tags should be matched against the group definitions", func() {
+				So(groupNodes(tx.Root), ShouldResemble, []string{"one", "block #1"})
+				So(groupNodes(tx.Root.Groups("block")[0]), ShouldResemble, []string{"two", "officer #1", "five"})
+			})
+
+			Convey("The tags should be found by id", func() {
+				tag, ok := tx.GetByID("3")
+				So(ok, ShouldBeTrue)
+				So(tag.Value, ShouldEqual, "c")
+			})
+		})
+	})
+
+	Convey("Given a tag map without groups", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When parsing a transaction", func() {
+			tx, err := tagMap.ParseTransaction("00010001a00020001b")
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Every tag should be directly within the root", func() {
+				So(tx.Root.HasGroups(), ShouldBeFalse)
+				So(groupNodes(tx.Root), ShouldResemble, []string{"one", "two"})
+			})
+		})
+	})
+}
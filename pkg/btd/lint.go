@@ -0,0 +1,106 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import "fmt"
+
+// LintKind identifies the kind of problem found when linting a tag map.
+type LintKind int
+
+const (
+	DuplicateID   LintKind = iota // the same id is mapped more than once
+	DuplicateName                 // the same name or alias is used more than once
+	IDWidth                       // the id does not have the number of digits expected by the dialect
+	IDCollision                   // differently written ids refer to the same number
+)
+
+var lintKindNames = [...]string{
+	DuplicateID:   "duplicate-id",
+	DuplicateName: "duplicate-name",
+	IDWidth:       "id-width",
+	IDCollision:   "id-collision",
+}
+
+func (k LintKind) String() string {
+	if int(k) < len(lintKindNames) {
+		return lintKindNames[k]
+	}
+
+	return fmt.Sprintf("LintKind(%d)", int(k))
+}
+
+// LintIssue is a problem found when linting a tag map.
+type LintIssue struct {
+	Kind    LintKind
+	Path    string
+	Line    int
+	Message string
+}
+
+func (i *LintIssue) Error() string {
+	return fmt.Sprintf("%s:%d: %s", i.Path, i.Line, i.Message)
+}
+
+// Lint checks the tag definitions loaded from the tag map at path for
+// mappings that would be silently overridden or ambiguous, and for ids that
+// do not match the width of dialect (nil means the Standard dialect). Issues
+// are returned in line order.
+func Lint(tags []TagDef, path string, dialect *Dialect) []*LintIssue {
+	if dialect == nil {
+		dialect = Standard
+	}
+
+	var issues []*LintIssue
+
+	report := func(kind LintKind, def TagDef, format string, args ...any) {
+		issues = append(issues, &LintIssue{Kind: kind, Path: path, Line: def.Line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	ids := make(map[string]TagDef)
+	names := make(map[string]TagDef)
+
+	for _, def := range tags {
+		if len(def.ID) != dialect.IDWidth {
+			report(IDWidth, def, "id %s does not have %d digits", def.ID, dialect.IDWidth)
+		}
+
+		if prev, ok := ids[normaliseID(def.ID)]; ok {
+			if prev.ID == def.ID {
+				report(DuplicateID, def, "id %s is already mapped to %s on line %d", def.ID, prev.Name, prev.Line)
+			} else {
+				report(IDCollision, def, "id %s refers to the same tag as id %s on line %d", def.ID, prev.ID, prev.Line)
+			}
+		} else {
+			ids[normaliseID(def.ID)] = def
+		}
+
+		for _, name := range append([]string{def.Name}, def.Aliases...) {
+			if prev, ok := names[name]; ok {
+				report(DuplicateName, def, "name %s is already used for id %s on line %d", name, prev.ID, prev.Line)
+			} else {
+				names[name] = def
+			}
+		}
+	}
+
+	return issues
+}
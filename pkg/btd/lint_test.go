@@ -0,0 +1,99 @@
+package btd
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLintWithConflictingMappings(t *testing.T) {
+	Convey("Given a tag map with duplicated ids and names", t, func() {
+
+		path := "testdata/tagmap_conflicts.dat"
+
+		tagMap, err := LoadTagMap(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When linting the tag map", func() {
+			issues := Lint(tagMap.Tags(), path, nil)
+
+			Convey("Every conflict should be reported in line order", func() {
+				So(issues, ShouldResemble, []*LintIssue{
+					{Kind: DuplicateID, Path: path, Line: 3, Message: "id 0001 is already mapped to one on line 1"},
+					{Kind: IDWidth, Path: path, Line: 4, Message: "id 010 does not have 4 digits"},
+					{Kind: IDCollision, Path: path, Line: 5, Message: "id 0010 refers to the same tag as id 010 on line 4"},
+					{Kind: DuplicateName, Path: path, Line: 6, Message: "name second is already used for id 0002 on line 2"},
+				})
+			})
+
+			Convey("The issues should describe their location", func() {
+				So(issues[0].Error(), ShouldEqual, path+":3: id 0001 is already mapped to one on line 1")
+				So(issues[0].Kind.String(), ShouldEqual, "duplicate-id")
+			})
+		})
+
+		Convey("When linting the tag map against a dialect with 3-digit ids", func() {
+			issues := Lint(tagMap.Tags(), path, &Dialect{IDWidth: 3})
+
+			Convey("The 4-digit ids should be reported instead", func() {
+				var lines []int
+				for _, issue := range issues {
+					if issue.Kind == IDWidth {
+						lines = append(lines, issue.Line)
+					}
+				}
+				So(lines, ShouldResemble, []int{1, 2, 3, 5, 6})
+			})
+		})
+	})
+
+	Convey("Given a tag map without conflicts", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When linting the tag map", func() {
+			issues := Lint(tagMap.Tags(), "testdata/tagmap.dat", nil)
+
+			Convey("No issues should be reported", func() {
+				So(issues, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestUnitLoadTagMapStrictly(t *testing.T) {
+	Convey("Given a strict tag map loader", t, func() {
+
+		loader := &TagMapLoader{Strict: true}
+
+		Convey("When loading a tag map with conflicts", func() {
+			tagMap, err := loader.Load("testdata/tagmap_conflicts.dat")
+
+			Convey("The tag map should be nil", func() {
+				So(tagMap, ShouldBeNil)
+			})
+
+			Convey("The error should wrap each issue", func() {
+				var issue *LintIssue
+				So(errors.As(err, &issue), ShouldBeTrue)
+				So(issue.Kind, ShouldEqual, DuplicateID)
+				So(err.Error(), ShouldContainSubstring, "tagmap_conflicts.dat:6: name second")
+			})
+		})
+
+		Convey("When loading a tag map without conflicts", func() {
+			tagMap, err := loader.Load("testdata/tagmap.dat")
+
+			Convey("The tag map should be loaded", func() {
+				So(tagMap, ShouldNotBeNil)
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import "fmt"
+
+// UnknownTagPolicy determines how a Decoder handles tags whose id is not
+// found in the tag map.
+type UnknownTagPolicy int
+
+const (
+	// UnknownTagError reports the unknown id as a *ParseError.
+	UnknownTagError UnknownTagPolicy = iota
+	// UnknownTagWarn keeps the tag under a placeholder name and records the
+	// unknown id in Transaction.Warnings.
+	UnknownTagWarn
+	// UnknownTagPlaceholder keeps the tag under a placeholder name.
+	UnknownTagPlaceholder
+	// UnknownTagSkip silently discards the tag.
+	UnknownTagSkip
+)
+
+var unknownTagPolicyNames = []string{"error", "warn", "placeholder", "skip"}
+
+func (p UnknownTagPolicy) String() string {
+	if int(p) < len(unknownTagPolicyNames) {
+		return unknownTagPolicyNames[p]
+	}
+
+	return "unknown"
+}
+
+// ParseUnknownTagPolicy returns the policy with the given name.
+func ParseUnknownTagPolicy(name string) (UnknownTagPolicy, error) {
+	for i, n := range unknownTagPolicyNames {
+		if n == name {
+			return UnknownTagPolicy(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown tag policy must be one of %v: %s", unknownTagPolicyNames, name)
+}
+
+// PlaceholderName returns the name given to a tag with an unknown id.
+func PlaceholderName(id string) string {
+	return "UNKNOWN_" + id
+}
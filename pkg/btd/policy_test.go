@@ -0,0 +1,108 @@
+package btd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitParseUnknownTagPolicy(t *testing.T) {
+	Convey("Given the name of each unknown tag policy", t, func() {
+
+		Convey("When parsing the names", func() {
+
+			Convey("The matching policies should be returned", func() {
+				for _, policy := range []UnknownTagPolicy{UnknownTagError, UnknownTagWarn, UnknownTagPlaceholder, UnknownTagSkip} {
+					parsed, err := ParseUnknownTagPolicy(policy.String())
+
+					So(err, ShouldBeNil)
+					So(parsed, ShouldEqual, policy)
+				}
+			})
+		})
+	})
+
+	Convey("Given an invalid unknown tag policy name", t, func() {
+
+		Convey("When parsing the name", func() {
+			_, err := ParseUnknownTagPolicy("ignore")
+
+			Convey("The error should describe the problem", func() {
+				So(err, ShouldEqual, errors.New("unknown tag policy must be one of [error warn placeholder skip]: ignore"))
+			})
+		})
+	})
+}
+
+func TestUnitDecodeWithUnknownTagPolicies(t *testing.T) {
+	Convey("Given a transaction containing an unknown tag id", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := "00010001a00990001b00020001c"
+
+		decode := func(policy UnknownTagPolicy) (*Transaction, error) {
+			decoder := NewDecoder(strings.NewReader(input), tagMap)
+			decoder.UnknownTags = policy
+
+			return decoder.Decode()
+		}
+
+		Convey("When decoding with the error policy", func() {
+			tx, err := decode(UnknownTagError)
+
+			Convey("The unknown id should be reported as an error", func() {
+				So(tx, ShouldBeNil)
+				So(err.Error(), ShouldEqual, "unknown id: 0099")
+			})
+		})
+
+		Convey("When decoding with the warn policy", func() {
+			tx, err := decode(UnknownTagWarn)
+
+			Convey("The tag should be kept under a placeholder name", func() {
+				So(err, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, TagData{
+					{"0001", "one", "0001", "a"},
+					{"0099", "UNKNOWN_0099", "0001", "b"},
+					{"0002", "two", "0001", "c"},
+				})
+			})
+
+			Convey("The unknown id should be reported as a warning", func() {
+				So(len(tx.Warnings), ShouldEqual, 1)
+				So(tx.Warnings[0].Kind, ShouldEqual, UnknownID)
+				So(tx.Warnings[0].ID, ShouldEqual, "0099")
+			})
+		})
+
+		Convey("When decoding with the placeholder policy", func() {
+			tx, err := decode(UnknownTagPlaceholder)
+
+			Convey("The tag should be kept under a placeholder name without warning", func() {
+				So(err, ShouldBeNil)
+				So(tx.Tags[1].Name, ShouldEqual, "UNKNOWN_0099")
+				So(tx.Tags[1].Value, ShouldEqual, "b")
+				So(tx.Warnings, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When decoding with the skip policy", func() {
+			tx, err := decode(UnknownTagSkip)
+
+			Convey("The tag should be discarded", func() {
+				So(err, ShouldBeNil)
+				So(tx.TagData(), ShouldResemble, TagData{
+					{"0001", "one", "0001", "a"},
+					{"0002", "two", "0001", "c"},
+				})
+				So(tx.Warnings, ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -24,3 +24,28 @@ package btd
 type Renderer interface {
 	Render(data TagData) string
 }
+
+// TransactionRenderer is implemented by renderers that can render a parsed
+// Transaction directly, including any problems found while decoding it.
+type TransactionRenderer interface {
+	Renderer
+	RenderTransaction(tx *Transaction) string
+}
+
+// TagMapRenderer is implemented by renderers that can render the tag
+// definitions of a tag map.
+type TagMapRenderer interface {
+	RenderTagMap(tags []TagDef) string
+}
+
+// TagMapDiffRenderer is implemented by renderers that can render the changes
+// between two versions of a tag map.
+type TagMapDiffRenderer interface {
+	RenderTagMapDiff(changes []TagMapChange) string
+}
+
+// ViolationRenderer is implemented by renderers that can render the schema
+// violations found in a transaction.
+type ViolationRenderer interface {
+	RenderViolations(violations []*Violation) string
+}
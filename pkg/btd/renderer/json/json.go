@@ -0,0 +1,297 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package json
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/btd/rules"
+)
+
+// JSON renders business transaction data and tag maps as indented JSON.
+type JSON struct{}
+
+func New() *JSON {
+	return &JSON{}
+}
+
+type tagJSON struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Length int    `json:"length"`
+	Value  string `json:"value"`
+	Offset *int   `json:"offset,omitempty"`
+
+	Decoded     any    `json:"decoded,omitempty"`
+	DecodeError string `json:"decodeError,omitempty"`
+}
+
+type groupJSON struct {
+	Name       string      `json:"name"`
+	Occurrence int         `json:"occurrence"`
+	Tags       []tagJSON   `json:"tags"`
+	Groups     []groupJSON `json:"groups,omitempty"`
+}
+
+type problemJSON struct {
+	Offset  int    `json:"offset"`
+	ID      string `json:"id,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+type typeJSON struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Missing     []string  `json:"missing,omitempty"`
+	Unexpected  []tagJSON `json:"unexpected,omitempty"`
+}
+
+type transactionJSON struct {
+	Line     int               `json:"line,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Type     *typeJSON         `json:"type,omitempty"`
+	Tags     []tagJSON         `json:"tags"`
+	Groups   []groupJSON       `json:"groups,omitempty"`
+	Errors   []problemJSON     `json:"errors,omitempty"`
+	Warnings []problemJSON     `json:"warnings,omitempty"`
+}
+
+type tagDefJSON struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Layout      string     `json:"layout,omitempty"`
+	Codes       []btd.Code `json:"codes,omitempty"`
+	Line        int        `json:"line,omitempty"`
+	Source      string     `json:"source,omitempty"`
+}
+
+type changeJSON struct {
+	Kind     string `json:"change"`
+	ID       string `json:"id"`
+	OldName  string `json:"oldName,omitempty"`
+	NewName  string `json:"newName,omitempty"`
+	OldLine  int    `json:"oldLine,omitempty"`
+	NewLine  int    `json:"newLine,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+type violationJSON struct {
+	Offset  *int   `json:"offset,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Tag     string `json:"tag"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type failureJSON struct {
+	ID       string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Render renders each row of data as an object. Length fields that are not
+// decimal are rendered as zero.
+func (j *JSON) Render(data btd.TagData) string {
+	tags := []tagJSON{}
+
+	for _, row := range data {
+		length, _ := strconv.Atoi(row[2])
+		tags = append(tags, tagJSON{ID: row[0], Name: row[1], Length: length, Value: row[3]})
+	}
+
+	return marshal(tags)
+}
+
+// RenderTransaction renders the tags of tx, the metadata of the log line it
+// was extracted from, its detected type, the groups they are arranged in,
+// and any problems found while decoding it.
+func (j *JSON) RenderTransaction(tx *btd.Transaction) string {
+	out := transactionJSON{
+		Line:     tx.Line,
+		Tags:     tagsJSON(tx.Tags),
+		Errors:   problemsJSON(tx.Errors),
+		Warnings: problemsJSON(tx.Warnings),
+	}
+
+	if tx.Root != nil {
+		out.Groups = groupsJSON(tx.Root)
+	}
+
+	if len(tx.Metadata) > 0 {
+		out.Metadata = make(map[string]string)
+		for _, field := range tx.Metadata {
+			out.Metadata[field.Name] = field.Value
+		}
+	}
+
+	if tx.Type != nil {
+		out.Type = &typeJSON{
+			Name:        tx.Type.Type.Name,
+			Description: tx.Type.Type.Description,
+			Missing:     tx.Type.Missing,
+		}
+
+		if len(tx.Type.Unexpected) > 0 {
+			out.Type.Unexpected = tagsJSON(tx.Type.Unexpected)
+		}
+	}
+
+	return marshal(out)
+}
+
+// RenderTagMap renders each tag definition as an object.
+func (j *JSON) RenderTagMap(tags []btd.TagDef) string {
+	defs := []tagDefJSON{}
+
+	for _, tag := range tags {
+		defs = append(defs, tagDefJSON(tag))
+	}
+
+	return marshal(defs)
+}
+
+// RenderTagMapDiff renders each change between two versions of a tag map as
+// an object.
+func (j *JSON) RenderTagMapDiff(changes []btd.TagMapChange) string {
+	out := []changeJSON{}
+
+	for _, change := range changes {
+		out = append(out, changeJSON{
+			Kind:     change.Kind.String(),
+			ID:       change.ID,
+			OldName:  change.OldName,
+			NewName:  change.NewName,
+			OldLine:  change.OldLine,
+			NewLine:  change.NewLine,
+			Breaking: change.Kind.Breaking(),
+		})
+	}
+
+	return marshal(out)
+}
+
+// RenderViolations renders each schema violation found in a transaction as
+// an object.
+func (j *JSON) RenderViolations(violations []*btd.Violation) string {
+	out := []violationJSON{}
+
+	for _, v := range violations {
+		item := violationJSON{ID: v.ID, Tag: v.Tag, Rule: v.Rule, Message: v.Message}
+		if v.Offset >= 0 {
+			item.Offset = &v.Offset
+		}
+		out = append(out, item)
+	}
+
+	return marshal(out)
+}
+
+// RenderRuleFailures renders each rule a transaction fails to satisfy as an
+// object.
+func (j *JSON) RenderRuleFailures(failures []*rules.Failure) string {
+	out := []failureJSON{}
+
+	for _, f := range failures {
+		out = append(out, failureJSON{ID: f.ID, Severity: f.Severity.String(), Message: f.Message})
+	}
+
+	return marshal(out)
+}
+
+func tagsJSON(tags []btd.Tag) []tagJSON {
+	out := []tagJSON{}
+
+	for _, tag := range tags {
+		item := tagJSON{ID: tag.ID, Name: tag.Name, Length: tag.DeclaredLength, Value: tag.Value, Offset: &tag.Offset}
+		item.Decoded = decodedJSON(tag.Decoded)
+		if tag.DecodeErr != nil {
+			item.DecodeError = tag.DecodeErr.Error()
+		}
+		out = append(out, item)
+	}
+
+	return out
+}
+
+// decodedJSON returns a decoded tag value in a form that marshals as its JSON
+// equivalent: numbers and booleans as themselves, and dates and codes as
+// their display form.
+func decodedJSON(v any) any {
+	switch v.(type) {
+	case int64, bool, nil:
+		return v
+	}
+
+	return btd.FormatDecoded(v)
+}
+
+// groupsJSON returns the groups nested directly within group.
+func groupsJSON(group *btd.Group) []groupJSON {
+	var out []groupJSON
+
+	for _, node := range group.Nodes {
+		if node.Group == nil {
+			continue
+		}
+
+		var tags []btd.Tag
+		for _, child := range node.Group.Nodes {
+			if child.Tag != nil {
+				tags = append(tags, *child.Tag)
+			}
+		}
+
+		out = append(out, groupJSON{
+			Name:       node.Group.Name,
+			Occurrence: node.Group.Occurrence,
+			Tags:       tagsJSON(tags),
+			Groups:     groupsJSON(node.Group),
+		})
+	}
+
+	return out
+}
+
+func problemsJSON(errs []*btd.ParseError) []problemJSON {
+	var out []problemJSON
+
+	for _, err := range errs {
+		out = append(out, problemJSON{Offset: err.Offset, ID: err.ID, Kind: err.Kind.String(), Message: err.Error()})
+	}
+
+	return out
+}
+
+func marshal(v any) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "null"
+	}
+
+	return string(data)
+}
@@ -0,0 +1,209 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/btd/rules"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitRenderTransaction(t *testing.T) {
+	Convey("Given a transaction containing a problem", t, func() {
+
+		tx := &btd.Transaction{
+			Line: 3,
+			Tags: []btd.Tag{{ID: "0001", Name: "one", DeclaredLength: 4, Value: "abcd"}},
+			Errors: []*btd.ParseError{
+				{Kind: btd.TruncatedData, Offset: 12, ID: "0002", Err: btd.ErrEndOfTransaction},
+			},
+		}
+
+		Convey("When rendering the transaction", func() {
+			var out map[string]any
+			err := json.Unmarshal([]byte(New().RenderTransaction(tx)), &out)
+
+			Convey("The output should be valid JSON", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The line and tags should be rendered", func() {
+				So(out["line"], ShouldEqual, 3.0)
+				So(out["tags"], ShouldResemble, []any{
+					map[string]any{"id": "0001", "name": "one", "length": 4.0, "value": "abcd", "offset": 0.0},
+				})
+			})
+
+			Convey("The problem should be rendered", func() {
+				So(out["errors"], ShouldResemble, []any{
+					map[string]any{"offset": 12.0, "id": "0002", "kind": "truncated-data", "message": "end of transaction"},
+				})
+			})
+
+			Convey("Groups, warnings, metadata and the type should be omitted", func() {
+				So(out, ShouldNotContainKey, "groups")
+				So(out, ShouldNotContainKey, "warnings")
+				So(out, ShouldNotContainKey, "metadata")
+				So(out, ShouldNotContainKey, "type")
+			})
+		})
+	})
+
+	Convey("Given a transaction extracted from a log line", t, func() {
+
+		tx := &btd.Transaction{
+			Line:     7,
+			Tags:     []btd.Tag{{ID: "0001", Name: "one", DeclaredLength: 1, Value: "a"}},
+			Metadata: []btd.LogField{{Name: "timestamp", Value: "09:00:01"}, {Name: "request_id", Value: "req-1"}},
+		}
+
+		Convey("When rendering the transaction", func() {
+			var out map[string]any
+			err := json.Unmarshal([]byte(New().RenderTransaction(tx)), &out)
+
+			Convey("The output should be valid JSON", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The metadata should be rendered as an object", func() {
+				So(out["metadata"], ShouldResemble, map[string]any{"timestamp": "09:00:01", "request_id": "req-1"})
+			})
+		})
+	})
+
+	Convey("Given a transaction with a detected type", t, func() {
+
+		tx := &btd.Transaction{
+			Tags: []btd.Tag{{ID: "0001", Name: "one", DeclaredLength: 4, Value: "AD01"}, {ID: "0009", Name: "nine", DeclaredLength: 1, Value: "i", Offset: 12}},
+			Type: &btd.TypeMatch{
+				Type:       &btd.TransactionType{Name: "address-change", Description: "Change of address"},
+				Missing:    []string{"two"},
+				Unexpected: []btd.Tag{{ID: "0009", Name: "nine", DeclaredLength: 1, Value: "i", Offset: 12}},
+			},
+		}
+
+		Convey("When rendering the transaction", func() {
+			var out map[string]any
+			err := json.Unmarshal([]byte(New().RenderTransaction(tx)), &out)
+
+			Convey("The output should be valid JSON", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The type should be rendered with its missing and unexpected tags", func() {
+				So(out["type"], ShouldResemble, map[string]any{
+					"name":        "address-change",
+					"description": "Change of address",
+					"missing":     []any{"two"},
+					"unexpected": []any{
+						map[string]any{"id": "0009", "name": "nine", "length": 1.0, "value": "i", "offset": 12.0},
+					},
+				})
+			})
+		})
+	})
+}
+
+func TestUnitRenderTagMap(t *testing.T) {
+	Convey("Given tag definitions", t, func() {
+
+		tags := []btd.TagDef{
+			{ID: "0001", Name: "company_number", Aliases: []string{"crn"}, Line: 1},
+			{ID: "0002", Name: "postcode", Line: 2},
+		}
+
+		Convey("When rendering the tag definitions", func() {
+			var out []map[string]any
+			err := json.Unmarshal([]byte(New().RenderTagMap(tags)), &out)
+
+			Convey("The output should be valid JSON", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Each definition should be rendered, omitting empty attributes", func() {
+				So(out, ShouldResemble, []map[string]any{
+					{"id": "0001", "name": "company_number", "aliases": []any{"crn"}, "line": 1.0},
+					{"id": "0002", "name": "postcode", "line": 2.0},
+				})
+			})
+		})
+	})
+
+	Convey("Given no tag definitions", t, func() {
+
+		Convey("When rendering the tag definitions", func() {
+			output := New().RenderTagMap(nil)
+
+			Convey("The output should be an empty array", func() {
+				So(output, ShouldEqual, "[]")
+			})
+		})
+	})
+}
+
+func TestUnitRenderRuleFailures(t *testing.T) {
+	Convey("Given rule failures", t, func() {
+
+		failures := []*rules.Failure{
+			{ID: "po-box-postcode", Severity: rules.Error, Message: "A postcode is required"},
+			{ID: "officer-limit", Severity: rules.Info, Message: "More than two officers"},
+		}
+
+		Convey("When rendering the failures", func() {
+			var out []map[string]any
+			err := json.Unmarshal([]byte(New().RenderRuleFailures(failures)), &out)
+
+			Convey("The output should be valid JSON", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Each failure should be rendered with its rule id and severity", func() {
+				So(out, ShouldResemble, []map[string]any{
+					{"rule": "po-box-postcode", "severity": "error", "message": "A postcode is required"},
+					{"rule": "officer-limit", "severity": "info", "message": "More than two officers"},
+				})
+			})
+		})
+	})
+}
+
+func TestUnitRenderDecodedValues(t *testing.T) {
+	Convey("Given a transaction with decoded tag values", t, func() {
+
+		tx := &btd.Transaction{
+			Tags: []btd.Tag{
+				{ID: "7004", Name: "received_date", Value: "22/11/33", Decoded: time.Date(2033, 11, 22, 0, 0, 0, 0, time.UTC)},
+				{ID: "7009", Name: "document_count", Value: "03", Decoded: int64(3)},
+				{ID: "7022", Name: "scanned_flag", Value: "0", Decoded: false},
+				{ID: "7023", Name: "paper_flag", Value: "1", Decoded: btd.Code{Value: "1", Label: "Paper filing"}},
+				{ID: "7024", Name: "submission_method", Value: "X", DecodeErr: errors.New(`unknown code: "X"`)},
+			},
+		}
+
+		Convey("When rendering the transaction", func() {
+			var out struct {
+				Tags []map[string]any `json:"tags"`
+			}
+			err := json.Unmarshal([]byte(New().RenderTransaction(tx)), &out)
+
+			Convey("The output should be valid JSON", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Each decoded value should be rendered as its JSON equivalent", func() {
+				So(out.Tags[0]["decoded"], ShouldEqual, "2033-11-22")
+				So(out.Tags[1]["decoded"], ShouldEqual, 3.0)
+				So(out.Tags[2]["decoded"], ShouldEqual, false)
+				So(out.Tags[3]["decoded"], ShouldEqual, "Paper filing")
+			})
+
+			Convey("A value that could not be decoded should be rendered with its error", func() {
+				So(out.Tags[4], ShouldNotContainKey, "decoded")
+				So(out.Tags[4]["decodeError"], ShouldEqual, `unknown code: "X"`)
+			})
+		})
+	})
+}
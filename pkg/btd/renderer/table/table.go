@@ -24,10 +24,14 @@ package table
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
 	"github.com/companieshouse/btd-cli/pkg/btd"
+	"github.com/companieshouse/btd-cli/pkg/btd/rules"
 	"golang.org/x/term"
 )
 
@@ -35,8 +39,14 @@ const (
 	purple    = lipgloss.Color("99")
 	gray      = lipgloss.Color("245")
 	lightGray = lipgloss.Color("241")
+	red       = lipgloss.Color("203")
+	yellow    = lipgloss.Color("221")
 )
 
+// groupIndent is the indentation applied to the XML tag of grouped tags for
+// each level of nesting.
+const groupIndent = "  "
+
 type ColumnID int
 
 const (
@@ -44,6 +54,7 @@ const (
 	xmlTagColumn
 	lengthColumn
 	dataColumn
+	decodedColumn
 )
 
 type Table struct{}
@@ -53,23 +64,43 @@ func New() *Table {
 }
 
 func (t *Table) Render(data btd.TagData) string {
+	return t.render(data, nil, nil)
+}
+
+// render renders rows of tag data; rows whose index is present in sections
+// are group headings, and the XML tag column is left-aligned to show the
+// indentation of grouped tags. Rows with a fifth column are rendered with a
+// Decoded column, highlighted for rows whose index is present in invalid.
+func (t *Table) render(data btd.TagData, sections map[int]bool, invalid map[int]bool) string {
 	re := lipgloss.NewRenderer(os.Stdout)
 
 	var (
-		IDColumnWidth     = 6
-		XMLTagColumnWidth = 20
-		LengthColumnWidth = 8
-		DataColumnWidth   = 10 // minimum width; dynamically scaled below
-		ColumnPadding     = 5
+		IDColumnWidth      = 6
+		XMLTagColumnWidth  = 20
+		LengthColumnWidth  = 8
+		DataColumnWidth    = 10 // minimum width; dynamically scaled below
+		DecodedColumnWidth = 0
+		ColumnPadding      = 5
 	)
 
+	headers := []string{"ID", "XML Tag", "Length", "Data"}
+
+	if len(data) > 0 && len(data[0]) > int(decodedColumn) {
+		headers = append(headers, "Decoded")
+
+		for _, row := range data {
+			DecodedColumnWidth = max(DecodedColumnWidth, lipgloss.Width(row[decodedColumn])+2)
+		}
+		ColumnPadding++
+	}
+
 	if max_data_length := data.GetMaxDataLength(); max_data_length > DataColumnWidth {
 		tty_width, _, err := term.GetSize(0)
 		if err != nil {
 			fmt.Fprint(os.Stderr, "Warning: unable to determine terminal width")
 		}
 
-		max_data_column_width := tty_width - IDColumnWidth - XMLTagColumnWidth - LengthColumnWidth - ColumnPadding
+		max_data_column_width := tty_width - IDColumnWidth - XMLTagColumnWidth - LengthColumnWidth - DecodedColumnWidth - ColumnPadding
 
 		if max_data_length > max_data_column_width {
 			DataColumnWidth = max_data_column_width
@@ -85,12 +116,20 @@ func (t *Table) Render(data btd.TagData) string {
 		XMLTagColumnStyle = re.NewStyle().Width(XMLTagColumnWidth).Align(lipgloss.Center)
 		LengthColumnStyle = re.NewStyle().Width(LengthColumnWidth).Align(lipgloss.Center)
 		DataColumnStyle   = re.NewStyle().Width(DataColumnWidth)
+		DecodedStyle      = re.NewStyle().Padding(0, 1)
+		InvalidStyle      = re.NewStyle().Foreground(red).Padding(0, 1)
 
 		OddRowStyle = re.NewStyle().Foreground(gray)
 
 		EvenRowStyle = re.NewStyle().Foreground(lightGray)
+
+		SectionRowStyle = re.NewStyle().Foreground(purple).Bold(true)
 	)
 
+	if sections != nil {
+		XMLTagColumnStyle = XMLTagColumnStyle.Align(lipgloss.Left)
+	}
+
 	return table.New().
 		Border(lipgloss.NormalBorder()).
 		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
@@ -100,6 +139,8 @@ func (t *Table) Render(data btd.TagData) string {
 			switch {
 			case row == table.HeaderRow:
 				style = style.Inherit(HeaderStyle)
+			case sections[row]:
+				style = style.Inherit(SectionRowStyle)
 			case row%2 == 0:
 				style = style.Inherit(EvenRowStyle)
 			case row%2 != 0:
@@ -115,11 +156,379 @@ func (t *Table) Render(data btd.TagData) string {
 				style = style.Inherit(LengthColumnStyle)
 			case dataColumn:
 				style = style.Inherit(DataColumnStyle)
+			case decodedColumn:
+				style = DecodedStyle.Inherit(style)
+				if invalid[row] {
+					style = InvalidStyle.Inherit(style)
+				}
 			}
 
 			return style
 		}).
-		Headers("ID", "XML Tag", "Length", "Data").
+		Headers(headers...).
 		Rows(data...).
 		String()
 }
+
+// RenderTransaction renders the tags of tx followed by a table of any
+// problems found while decoding it, beneath the metadata of the log line it
+// was extracted from and its type if one was detected.
+// Tags within groups declared in the tag map are indented beneath a heading
+// row for each occurrence of the group.
+func (t *Table) RenderTransaction(tx *btd.Transaction) string {
+	var output string
+
+	if len(tx.Metadata) > 0 {
+		output = renderMetadata(tx.Metadata) + "\n"
+	}
+
+	if tx.Type != nil {
+		output += renderTypeHeader(tx.Type) + "\n"
+	}
+
+	var (
+		rows     btd.TagData
+		sections map[int]bool
+		invalid  map[int]bool
+	)
+
+	if tx.Root != nil && tx.Root.HasGroups() {
+		rows, sections = groupRows(tx.Root, 0, nil, map[int]bool{})
+	} else {
+		rows = tx.TagData()
+	}
+
+	if slices.ContainsFunc(tx.Tags, func(tag btd.Tag) bool { return tag.Decoded != nil || tag.DecodeErr != nil }) {
+		rows, invalid = decodedRows(rows, sections, tx.Tags)
+	}
+
+	output += t.render(rows, sections, invalid)
+
+	if len(tx.Errors) > 0 {
+		output += "\n" + renderProblems(tx.Errors)
+	}
+
+	return output
+}
+
+// decodedRows adds the decoded value of each tag to its row, returning the
+// indexes of the rows whose value could not be decoded. Tags are expected in
+// the order their rows appear, ignoring group headings.
+func decodedRows(rows btd.TagData, sections map[int]bool, tags []btd.Tag) (btd.TagData, map[int]bool) {
+	invalid := make(map[int]bool)
+	next := 0
+
+	for i, row := range rows {
+		if sections[i] || next >= len(tags) {
+			rows[i] = append(row, "")
+			continue
+		}
+
+		tag := tags[next]
+		next++
+
+		if tag.DecodeErr != nil {
+			rows[i] = append(row, tag.DecodeErr.Error())
+			invalid[i] = true
+			continue
+		}
+
+		rows[i] = append(row, btd.FormatDecoded(tag.Decoded))
+	}
+
+	return rows, invalid
+}
+
+// renderMetadata renders the values captured from the log line a
+// transaction was extracted from on a single line.
+func renderMetadata(fields []btd.LogField) string {
+	style := lipgloss.NewRenderer(os.Stdout).NewStyle().Foreground(gray)
+
+	var parts []string
+	for _, field := range fields {
+		parts = append(parts, field.Name+": "+field.Value)
+	}
+
+	return style.Render(strings.Join(parts, "  "))
+}
+
+// renderTypeHeader renders the detected type of a transaction, followed by
+// any missing required or unexpected tags.
+func renderTypeHeader(match *btd.TypeMatch) string {
+	re := lipgloss.NewRenderer(os.Stdout)
+
+	var (
+		TypeStyle    = re.NewStyle().Foreground(purple).Bold(true)
+		ProblemStyle = re.NewStyle().Foreground(red)
+	)
+
+	lines := []string{TypeStyle.Render("Transaction type: " + match.Type.String())}
+
+	if len(match.Missing) > 0 {
+		lines = append(lines, ProblemStyle.Render("Missing required tags: "+strings.Join(match.Missing, ", ")))
+	}
+
+	if len(match.Unexpected) > 0 {
+		var tags []string
+		for _, tag := range match.Unexpected {
+			tags = append(tags, fmt.Sprintf("%s (%s)", tag.Name, tag.ID))
+		}
+		lines = append(lines, ProblemStyle.Render("Unexpected tags: "+strings.Join(tags, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// groupRows appends a row for each tag within group, indented by depth, with
+// a heading row preceding each nested group.
+func groupRows(group *btd.Group, depth int, rows btd.TagData, sections map[int]bool) (btd.TagData, map[int]bool) {
+	indent := strings.Repeat(groupIndent, depth)
+
+	for _, node := range group.Nodes {
+		if node.Group != nil {
+			sections[len(rows)] = true
+			rows = append(rows, []string{"", indent + "▸ " + node.Group.String(), "", ""})
+			rows, sections = groupRows(node.Group, depth+1, rows, sections)
+			continue
+		}
+
+		tag := node.Tag
+		rows = append(rows, []string{tag.ID, indent + tag.Name, tag.LengthField(), tag.Value})
+	}
+
+	return rows, sections
+}
+
+// RenderTagMap renders the id, name, type, aliases and description of each
+// tag definition, along with its date layout or code list and the file it
+// was loaded from if known.
+func (t *Table) RenderTagMap(tags []btd.TagDef) string {
+	re := lipgloss.NewRenderer(os.Stdout)
+
+	var (
+		HeaderStyle  = re.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
+		CellStyle    = re.NewStyle().Padding(0, 1)
+		CenterStyle  = re.NewStyle().Align(lipgloss.Center)
+		OddRowStyle  = re.NewStyle().Foreground(gray)
+		EvenRowStyle = re.NewStyle().Foreground(lightGray)
+	)
+
+	headers := []string{"ID", "XML Tag", "Type", "Aliases", "Description"}
+
+	values := slices.ContainsFunc(tags, func(tag btd.TagDef) bool { return len(tag.Layout) > 0 || len(tag.Codes) > 0 })
+	if values {
+		headers = append(headers, "Values")
+	}
+
+	sources := slices.ContainsFunc(tags, func(tag btd.TagDef) bool { return len(tag.Source) > 0 })
+	if sources {
+		headers = append(headers, "Source")
+	}
+
+	var rows [][]string
+	for _, tag := range tags {
+		row := []string{tag.ID, tag.Name, tag.Type, strings.Join(tag.Aliases, ", "), tag.Description}
+		if values {
+			row = append(row, tagValues(tag))
+		}
+		if sources {
+			row = append(row, fmt.Sprintf("%s:%d", tag.Source, tag.Line))
+		}
+		rows = append(rows, row)
+	}
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := CellStyle
+
+			switch {
+			case row == table.HeaderRow:
+				return style.Inherit(HeaderStyle)
+			case row%2 == 0:
+				style = style.Inherit(EvenRowStyle)
+			default:
+				style = style.Inherit(OddRowStyle)
+			}
+
+			if col == 0 {
+				style = style.Inherit(CenterStyle)
+			}
+
+			return style
+		}).
+		Headers(headers...).
+		Rows(rows...).
+		String()
+}
+
+// tagValues describes the values of a tag with a date layout or code list,
+// giving each code and its label on a separate line.
+func tagValues(tag btd.TagDef) string {
+	if len(tag.Layout) > 0 {
+		return "layout " + tag.Layout
+	}
+
+	var codes []string
+	for _, code := range tag.Codes {
+		codes = append(codes, code.Value+": "+code.Label)
+	}
+
+	return strings.Join(codes, "\n")
+}
+
+// RenderTagMapDiff renders each change between two versions of a tag map,
+// highlighting breaking changes.
+func (t *Table) RenderTagMapDiff(changes []btd.TagMapChange) string {
+	re := lipgloss.NewRenderer(os.Stdout)
+
+	var (
+		HeaderStyle   = re.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
+		CellStyle     = re.NewStyle().Padding(0, 1)
+		CenterStyle   = re.NewStyle().Align(lipgloss.Center)
+		BreakingStyle = re.NewStyle().Foreground(red)
+		AddedStyle    = re.NewStyle().Foreground(gray)
+	)
+
+	var rows [][]string
+	for _, change := range changes {
+		rows = append(rows, []string{change.Kind.String(), change.ID, change.OldName, change.NewName})
+	}
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := CellStyle
+
+			switch {
+			case row == table.HeaderRow:
+				return style.Inherit(HeaderStyle)
+			case changes[row].Kind.Breaking():
+				style = style.Inherit(BreakingStyle)
+			default:
+				style = style.Inherit(AddedStyle)
+			}
+
+			if col < 2 {
+				style = style.Inherit(CenterStyle)
+			}
+
+			return style
+		}).
+		Headers("Change", "ID", "Old XML Tag", "New XML Tag").
+		Rows(rows...).
+		String()
+}
+
+// RenderViolations renders each schema violation found in a transaction.
+func (t *Table) RenderViolations(violations []*btd.Violation) string {
+	re := lipgloss.NewRenderer(os.Stdout)
+
+	var (
+		HeaderStyle  = re.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
+		CellStyle    = re.NewStyle().Padding(0, 1)
+		CenterStyle  = re.NewStyle().Align(lipgloss.Center)
+		ProblemStyle = re.NewStyle().Foreground(red)
+	)
+
+	var rows [][]string
+	for _, v := range violations {
+		offset := ""
+		if v.Offset >= 0 {
+			offset = strconv.Itoa(v.Offset)
+		}
+		rows = append(rows, []string{offset, v.ID, v.Tag, v.Rule, v.Message})
+	}
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return CellStyle.Inherit(HeaderStyle)
+			case col < 2 || col == 3:
+				return CellStyle.Inherit(CenterStyle)
+			case col == 4:
+				return CellStyle.Inherit(ProblemStyle)
+			}
+			return CellStyle
+		}).
+		Headers("Offset", "ID", "XML Tag", "Rule", "Problem").
+		Rows(rows...).
+		String()
+}
+
+// RenderRuleFailures renders each rule a transaction fails to satisfy, with
+// the severity of the rule determining the colour of its message.
+func (t *Table) RenderRuleFailures(failures []*rules.Failure) string {
+	re := lipgloss.NewRenderer(os.Stdout)
+
+	var (
+		HeaderStyle = re.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
+		CellStyle   = re.NewStyle().Padding(0, 1)
+		CenterStyle = re.NewStyle().Align(lipgloss.Center)
+	)
+
+	severityStyles := map[rules.Severity]lipgloss.Style{
+		rules.Error:   re.NewStyle().Foreground(red),
+		rules.Warning: re.NewStyle().Foreground(yellow),
+		rules.Info:    re.NewStyle().Foreground(gray),
+	}
+
+	var rows [][]string
+	for _, f := range failures {
+		rows = append(rows, []string{f.ID, f.Severity.String(), f.Message})
+	}
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return CellStyle.Inherit(HeaderStyle)
+			case col == 0:
+				return CellStyle
+			case col == 1:
+				return CellStyle.Inherit(CenterStyle).Inherit(severityStyles[failures[row].Severity])
+			}
+			return CellStyle.Inherit(severityStyles[failures[row].Severity])
+		}).
+		Headers("Rule", "Severity", "Message").
+		Rows(rows...).
+		String()
+}
+
+func renderProblems(errs []*btd.ParseError) string {
+	re := lipgloss.NewRenderer(os.Stdout)
+
+	var (
+		HeaderStyle  = re.NewStyle().Foreground(purple).Bold(true).Align(lipgloss.Center)
+		CenterStyle  = re.NewStyle().Align(lipgloss.Center)
+		ProblemStyle = re.NewStyle().Foreground(red)
+	)
+
+	var rows [][]string
+	for _, err := range errs {
+		rows = append(rows, []string{strconv.Itoa(err.Offset), err.ID, err.Kind.String(), err.Error()})
+	}
+
+	return table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("99"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return HeaderStyle
+			case col < 3:
+				return CenterStyle
+			}
+			return ProblemStyle
+		}).
+		Headers("Offset", "ID", "Kind", "Problem").
+		Rows(rows...).
+		String()
+}
@@ -0,0 +1,258 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package rules
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+)
+
+// valueKind identifies the type of a value.
+type valueKind int
+
+const (
+	kindNull valueKind = iota // value of a tag missing from the transaction
+	kindBool
+	kindNumber
+	kindString
+)
+
+// value is the result of evaluating part of a rule expression.
+type value struct {
+	kind valueKind
+	b    bool
+	n    float64
+	s    string
+}
+
+func boolValue(b bool) value      { return value{kind: kindBool, b: b} }
+func numberValue(n float64) value { return value{kind: kindNumber, n: n} }
+func stringValue(s string) value  { return value{kind: kindString, s: s} }
+
+// truthy reports whether v is considered true where a condition is expected.
+// Missing tags, empty strings and zero are false.
+func (v value) truthy() bool {
+	switch v.kind {
+	case kindBool:
+		return v.b
+	case kindNumber:
+		return v.n != 0
+	case kindString:
+		return len(v.s) > 0
+	}
+	return false
+}
+
+// number returns v as a number, if it is one or is a string holding one.
+func (v value) number() (float64, bool) {
+	switch v.kind {
+	case kindNumber:
+		return v.n, true
+	case kindString:
+		n, err := strconv.ParseFloat(v.s, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// env holds the values of each tag in a transaction, keyed by name.
+type env map[string][]string
+
+// newEnv returns the environment for evaluating rules against data.
+func newEnv(data btd.TagData) env {
+	e := make(env)
+
+	for _, row := range data {
+		e[row[1]] = append(e[row[1]], row[3])
+	}
+
+	return e
+}
+
+// node is a node of a parsed rule expression.
+type node interface {
+	eval(e env) value
+}
+
+type literalNode struct {
+	value value
+}
+
+func (n *literalNode) eval(env) value {
+	return n.value
+}
+
+// identNode evaluates to the value of the first occurrence of the named tag.
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(e env) value {
+	values, ok := e[n.name]
+	if !ok {
+		return value{}
+	}
+	return stringValue(values[0])
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(e env) value {
+	return boolValue(!n.operand.eval(e).truthy())
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(e env) value {
+	return boolValue(n.left.eval(e).truthy() && n.right.eval(e).truthy())
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(e env) value {
+	return boolValue(n.left.eval(e).truthy() || n.right.eval(e).truthy())
+}
+
+// compareNode compares two values. Values are compared numerically if
+// either is a number and both can be read as one, and as strings otherwise.
+// A missing tag is equal only to another missing tag, and is neither less
+// nor greater than any value.
+type compareNode struct {
+	op          tokenKind
+	left, right node
+}
+
+func (n *compareNode) eval(e env) value {
+	left, right := n.left.eval(e), n.right.eval(e)
+
+	cmp, ok := compare(left, right)
+	if !ok {
+		switch n.op {
+		case tokenEq:
+			return boolValue(left.kind == kindNull && right.kind == kindNull)
+		case tokenNe:
+			return boolValue(left.kind != kindNull || right.kind != kindNull)
+		}
+		return boolValue(false)
+	}
+
+	switch n.op {
+	case tokenEq:
+		return boolValue(cmp == 0)
+	case tokenNe:
+		return boolValue(cmp != 0)
+	case tokenLt:
+		return boolValue(cmp < 0)
+	case tokenLe:
+		return boolValue(cmp <= 0)
+	case tokenGt:
+		return boolValue(cmp > 0)
+	}
+	return boolValue(cmp >= 0)
+}
+
+// compare returns -1, 0 or 1 according to whether a is less than, equal to
+// or greater than b, reporting false if the values cannot be compared.
+func compare(a, b value) (int, bool) {
+	if a.kind == kindNull || b.kind == kindNull {
+		return 0, false
+	}
+
+	if a.kind == kindNumber || b.kind == kindNumber {
+		x, okA := a.number()
+		y, okB := b.number()
+		if !okA || !okB {
+			return 0, false
+		}
+		return cmp.Compare(x, y), true
+	}
+
+	if a.kind == kindBool || b.kind == kindBool {
+		if a.kind != b.kind {
+			return 0, false
+		}
+		if a.b == b.b {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	return cmp.Compare(a.s, b.s), true
+}
+
+// presentNode reports whether the named tag occurs in the transaction.
+type presentNode struct {
+	tag *identNode
+}
+
+func (n *presentNode) eval(e env) value {
+	_, ok := e[n.tag.name]
+	return boolValue(ok)
+}
+
+// countNode evaluates to the number of occurrences of the named tag.
+type countNode struct {
+	tag *identNode
+}
+
+func (n *countNode) eval(e env) value {
+	return numberValue(float64(len(e[n.tag.name])))
+}
+
+// lenNode evaluates to the number of characters in its operand, or zero for
+// a missing tag.
+type lenNode struct {
+	operand node
+}
+
+func (n *lenNode) eval(e env) value {
+	v := n.operand.eval(e)
+	if v.kind != kindString {
+		return numberValue(0)
+	}
+	return numberValue(float64(utf8.RuneCountInString(v.s)))
+}
+
+// matchesNode reports whether its operand matches a regular expression. A
+// missing tag never matches.
+type matchesNode struct {
+	operand node
+	pattern *regexp.Regexp
+}
+
+func (n *matchesNode) eval(e env) value {
+	v := n.operand.eval(e)
+	if v.kind != kindString {
+		return boolValue(false)
+	}
+	return boolValue(n.pattern.MatchString(v.s))
+}
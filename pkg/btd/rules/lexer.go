@@ -0,0 +1,190 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tokenKind identifies the kind of a lexical token.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenTrue
+	tokenFalse
+	tokenAnd    // &&
+	tokenOr     // ||
+	tokenNot    // !
+	tokenEq     // ==
+	tokenNe     // !=
+	tokenLt     // <
+	tokenLe     // <=
+	tokenGt     // >
+	tokenGe     // >=
+	tokenLParen // (
+	tokenRParen // )
+	tokenComma  // ,
+)
+
+var tokenNames = []string{
+	"end of expression", "identifier", "string", "number", "true", "false",
+	"&&", "||", "!", "==", "!=", "<", "<=", ">", ">=", "(", ")", ",",
+}
+
+func (k tokenKind) String() string {
+	return tokenNames[k]
+}
+
+// token is a lexical token of a rule expression. Pos is the 1-based column
+// at which the token starts.
+type token struct {
+	kind tokenKind
+	text string // identifier name, unquoted string or number as written
+	pos  int
+}
+
+// operators maps each operator to its token kind, longest first so that
+// two-character operators are preferred.
+var operators = []struct {
+	text string
+	kind tokenKind
+}{
+	{"&&", tokenAnd}, {"||", tokenOr}, {"==", tokenEq}, {"!=", tokenNe},
+	{"<=", tokenLe}, {">=", tokenGe}, {"!", tokenNot}, {"<", tokenLt},
+	{">", tokenGt}, {"(", tokenLParen}, {")", tokenRParen}, {",", tokenComma},
+}
+
+// lex splits a rule expression into tokens, ending with a tokenEOF token.
+func lex(expr string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(expr)
+	i := 0
+
+	for i < len(runes) {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+
+			text := string(runes[start:i])
+			switch text {
+			case "true":
+				tokens = append(tokens, token{kind: tokenTrue, text: text, pos: pos})
+			case "false":
+				tokens = append(tokens, token{kind: tokenFalse, text: text, pos: pos})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, text: text, pos: pos})
+			}
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: pos})
+
+		case r == '"' || r == '\'':
+			text, n, err := lexString(runes[i:])
+			if err != nil {
+				return nil, &SyntaxError{Pos: pos, Err: err}
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: pos})
+			i += n
+
+		default:
+			kind, n := lexOperator(runes[i:])
+			if n == 0 {
+				return nil, &SyntaxError{Pos: pos, Err: fmt.Errorf("unexpected character %q", r)}
+			}
+
+			tokens = append(tokens, token{kind: kind, text: string(runes[i : i+n]), pos: pos})
+			i += n
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// lexString reads the quoted string at the start of runes, in which \ may be
+// used to escape the quote character or a backslash. It returns the unquoted
+// string and the number of runes consumed.
+func lexString(runes []rune) (string, int, error) {
+	var text strings.Builder
+
+	quote := runes[0]
+	escaped := false
+
+	for i := 1; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case escaped:
+			text.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == quote:
+			return text.String(), i + 1, nil
+		default:
+			text.WriteRune(r)
+		}
+	}
+
+	return "", 0, errors.New("unterminated string")
+}
+
+// lexOperator returns the operator at the start of runes and its length, or
+// zero if there is none.
+func lexOperator(runes []rune) (tokenKind, int) {
+	for _, op := range operators {
+		if strings.HasPrefix(string(runes[:min(len(runes), 2)]), op.text) {
+			return op.kind, len([]rune(op.text))
+		}
+	}
+
+	return 0, 0
+}
+
+// isIdentRune reports whether r may appear after the first character of a
+// tag name.
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,310 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// SyntaxError describes a rule expression that could not be parsed. Pos is
+// the 1-based column at which the problem was found.
+type SyntaxError struct {
+	Pos int
+	Err error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %v", e.Pos, e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// expression is a parsed rule expression, along with the tags it refers to.
+type expression struct {
+	root   node
+	idents []*identNode
+}
+
+// parser is a recursive descent parser for rule expressions:
+//
+//	or         = and { "||" and }
+//	and        = comparison { "&&" comparison }
+//	comparison = unary [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) unary ]
+//	unary      = "!" unary | primary
+//	primary    = literal | name | name "(" [ or { "," or } ] ")" | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+	idents []*identNode
+}
+
+// parse parses a rule expression.
+func parse(expr string) (*expression, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+
+	return &expression{root: root, idents: p.idents}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// expect consumes the next token, which must be of the given kind.
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, &SyntaxError{Pos: tok.pos, Err: fmt.Errorf("expected %s: found %s", kind, describe(tok))}
+	}
+	return tok, nil
+}
+
+func (p *parser) unexpected(tok token) error {
+	return &SyntaxError{Pos: tok.pos, Err: fmt.Errorf("unexpected %s", describe(tok))}
+}
+
+// describe returns a description of tok for use in error messages.
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenIdent, tokenNumber:
+		return fmt.Sprintf("%s %s", tok.kind, tok.text)
+	case tokenString:
+		return fmt.Sprintf("string %q", tok.text)
+	case tokenEOF:
+		return tok.kind.String()
+	}
+	return fmt.Sprintf("%q", tok.kind.String())
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op := p.peek().kind; op {
+	case tokenEq, tokenNe, tokenLt, tokenLe, tokenGt, tokenGe:
+		p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: op, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenTrue, tokenFalse:
+		return &literalNode{value: boolValue(tok.kind == tokenTrue)}, nil
+
+	case tokenString:
+		return &literalNode{value: stringValue(tok.text)}, nil
+
+	case tokenNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: tok.pos, Err: fmt.Errorf("invalid number: %s", tok.text)}
+		}
+		return &literalNode{value: numberValue(n)}, nil
+
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(tok)
+		}
+
+		ident := &identNode{name: tok.text}
+		p.idents = append(p.idents, ident)
+		return ident, nil
+
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	return nil, p.unexpected(tok)
+}
+
+// parseCall parses the arguments of a call to the function named by name,
+// checking them against the function's signature.
+func (p *parser) parseCall(name token) (node, error) {
+	p.next()
+
+	var args []node
+	var argTokens []token
+
+	if p.peek().kind != tokenRParen {
+		for {
+			argTokens = append(argTokens, p.peek())
+
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if _, err := p.expect(tokenRParen); err != nil {
+		return nil, err
+	}
+
+	callError := func(format string, a ...any) error {
+		return &SyntaxError{Pos: name.pos, Err: fmt.Errorf("%s: "+format, append([]any{name.text}, a...)...)}
+	}
+
+	switch name.text {
+	case "present", "count":
+		if len(args) != 1 {
+			return nil, callError("expected 1 argument: found %d", len(args))
+		}
+
+		ident, ok := args[0].(*identNode)
+		if !ok {
+			return nil, callError("argument must be a tag name")
+		}
+
+		if name.text == "present" {
+			return &presentNode{tag: ident}, nil
+		}
+		return &countNode{tag: ident}, nil
+
+	case "len":
+		if len(args) != 1 {
+			return nil, callError("expected 1 argument: found %d", len(args))
+		}
+		return &lenNode{operand: args[0]}, nil
+
+	case "matches":
+		if len(args) != 2 {
+			return nil, callError("expected 2 arguments: found %d", len(args))
+		}
+
+		pattern, ok := args[1].(*literalNode)
+		if !ok || pattern.value.kind != kindString {
+			return nil, callError("pattern must be a string")
+		}
+
+		re, err := regexp.Compile(pattern.value.s)
+		if err != nil {
+			return nil, &SyntaxError{Pos: argTokens[1].pos, Err: fmt.Errorf("invalid pattern: %w", err)}
+		}
+		return &matchesNode{operand: args[0], pattern: re}, nil
+	}
+
+	return nil, &SyntaxError{Pos: name.pos, Err: errors.New("unknown function: " + name.text)}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package rules evaluates cross-field rules against business transaction
+// data. Each rule is an expression over the values of a transaction's tags,
+// referred to by XML tag name, such as:
+//
+//	!present(po_box) || present(postcode)
+//	len(company_name) <= 160 && matches(company_number, "^[A-Z0-9]{8}$")
+//
+// Expressions may use string, number and boolean literals, the comparison
+// operators ==, !=, <, <=, > and >=, the logical operators &&, || and !, and
+// the functions present(tag), count(tag), len(value) and matches(value,
+// pattern). A tag name evaluates to the value of its first occurrence.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	"go.yaml.in/yaml/v3"
+)
+
+// Severity is the severity of a rule that is not satisfied.
+type Severity int
+
+const (
+	// Error causes validation to fail.
+	Error Severity = iota
+	// Warning is reported without failing validation.
+	Warning
+	// Info is reported without failing validation.
+	Info
+)
+
+var severityNames = []string{"error", "warning", "info"}
+
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+
+	return "unknown"
+}
+
+// ParseSeverity returns the severity with the given name.
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if n == name {
+			return Severity(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("severity must be one of %v: %s", severityNames, name)
+}
+
+func (s *Severity) UnmarshalYAML(node *yaml.Node) error {
+	severity, err := ParseSeverity(node.Value)
+	if err != nil {
+		return err
+	}
+
+	*s = severity
+	return nil
+}
+
+// Rule is a condition a transaction must satisfy.
+type Rule struct {
+	ID       string   `yaml:"id"`
+	Severity Severity `yaml:"severity"` // defaults to error
+	Message  string   `yaml:"message"`  // defaults to the expression
+	When     string   `yaml:"when"`     // optional expression limiting the transactions the rule applies to
+	Expr     string   `yaml:"expr"`     // expression the transaction must satisfy
+
+	when *expression
+	expr *expression
+}
+
+// RuleSet is a set of rules loaded from a rules file.
+type RuleSet struct {
+	Rules []*Rule `yaml:"rules"`
+
+	path string
+}
+
+// Failure is a rule not satisfied by a transaction.
+type Failure struct {
+	ID       string
+	Severity Severity
+	Message  string
+}
+
+func (f *Failure) Error() string {
+	return f.ID + ": " + f.Message
+}
+
+// Renderer is implemented by renderers that can render the rules a
+// transaction fails to satisfy.
+type Renderer interface {
+	RenderRuleFailures(failures []*Failure) string
+}
+
+// LoadRules loads the YAML rules file at path, resolving the tag names the
+// rules refer to using tagMap so that aliases may be used.
+func LoadRules(path string, tagMap btd.TagMap) (*RuleSet, error) {
+	if len(path) == 0 {
+		return nil, errors.New("path cannot be empty")
+	}
+
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rules file: %s", path)
+	}
+	defer fp.Close()
+
+	set := &RuleSet{path: path}
+
+	decoder := yaml.NewDecoder(fp)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(set); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+
+	for i, rule := range set.Rules {
+		if rule == nil || len(rule.ID) == 0 {
+			return nil, fmt.Errorf("%s: rule %d: id cannot be empty", path, i+1)
+		}
+
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("%s: duplicate rule id: %s", path, rule.ID)
+		}
+		seen[rule.ID] = true
+
+		if err := rule.compile(tagMap); err != nil {
+			return nil, fmt.Errorf("%s: rule %s: %w", path, rule.ID, err)
+		}
+	}
+
+	return set, nil
+}
+
+// Path returns the path the rules were loaded from.
+func (s *RuleSet) Path() string {
+	return s.path
+}
+
+// compile parses the expressions of the rule and resolves the tags they
+// refer to.
+func (r *Rule) compile(tagMap btd.TagMap) error {
+	if len(r.Expr) == 0 {
+		return errors.New("expr cannot be empty")
+	}
+
+	var err error
+
+	if r.expr, err = compile(r.Expr, tagMap); err != nil {
+		return fmt.Errorf("expr: %w", err)
+	}
+
+	if len(r.When) > 0 {
+		if r.when, err = compile(r.When, tagMap); err != nil {
+			return fmt.Errorf("when: %w", err)
+		}
+	}
+
+	if len(r.Message) == 0 {
+		r.Message = r.Expr
+	}
+
+	return nil
+}
+
+// compile parses expr, replacing any alias it uses with the tag's name.
+func compile(expr string, tagMap btd.TagMap) (*expression, error) {
+	parsed, err := parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ident := range parsed.idents {
+		id, err := tagMap.GetTagID(ident.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if ident.name, err = tagMap.GetTagName(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed, nil
+}
+
+// Evaluate checks data against each rule that applies to it, returning a
+// failure for each rule not satisfied, in the order the rules are defined.
+func (s *RuleSet) Evaluate(data btd.TagData) []*Failure {
+	var failures []*Failure
+
+	e := newEnv(data)
+
+	for _, rule := range s.Rules {
+		if rule.when != nil && !rule.when.root.eval(e).truthy() {
+			continue
+		}
+
+		if !rule.expr.root.eval(e).truthy() {
+			failures = append(failures, &Failure{ID: rule.ID, Severity: rule.Severity, Message: rule.Message})
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,195 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/companieshouse/btd-cli/pkg/btd"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// evaluate parses expr and evaluates it against data.
+func evaluate(t *testing.T, expr string, data btd.TagData) value {
+	parsed, err := parse(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed.root.eval(newEnv(data))
+}
+
+func TestUnitEvaluateExpression(t *testing.T) {
+	Convey("Given tag data", t, func() {
+
+		data := btd.TagData{
+			{"1000", "company_number", "0008", "01234567"},
+			{"2003", "country", "0005", "Wales"},
+			{"3001", "officer", "0001", "a"},
+			{"3001", "officer", "0001", "b"},
+		}
+
+		tests := []struct {
+			expr string
+			want bool
+		}{
+			{`present(country)`, true},
+			{`present(po_box)`, false},
+			{`!present(po_box) || present(postcode)`, true},
+			{`present(po_box) && present(postcode)`, false},
+			{`country == "Wales"`, true},
+			{`country != 'Wales'`, false},
+			{`country == "wales"`, false},
+			{`postcode == "Wales"`, false},
+			{`postcode != "Wales"`, true},
+			{`postcode < "Z"`, false},
+			{`company_number == 1234567`, true},
+			{`company_number > 1000000 && company_number < 2000000`, true},
+			{`company_number == "1234567"`, false},
+			{`count(officer) == 2`, true},
+			{`count(po_box) == 0`, true},
+			{`len(company_number) == 8`, true},
+			{`len(po_box) == 0`, true},
+			{`matches(company_number, "^[0-9]{8}$")`, true},
+			{`matches(po_box, ".*")`, false},
+			{`officer == "a"`, true},
+			{`!(country == "Wales" || country == "England")`, false},
+			{`country`, true},
+			{`po_box`, false},
+			{`true && !false`, true},
+		}
+
+		for _, test := range tests {
+			Convey("When evaluating "+test.expr, func() {
+				v := evaluate(t, test.expr, data)
+
+				Convey("The result should be correct", func() {
+					So(v.truthy(), ShouldEqual, test.want)
+				})
+			})
+		}
+	})
+}
+
+func TestUnitParseExpressionWithErrors(t *testing.T) {
+	Convey("Given malformed expressions", t, func() {
+
+		tests := []struct {
+			expr string
+			want string
+		}{
+			{`country == `, "column 12: unexpected end of expression"},
+			{`country = "Wales"`, `column 9: unexpected character '='`},
+			{`country == "Wales`, "column 12: unterminated string"},
+			{`(present(po_box)`, "column 17: expected ): found end of expression"},
+			{`present("po_box")`, "column 1: present: argument must be a tag name"},
+			{`present(po_box, postcode)`, "column 1: present: expected 1 argument: found 2"},
+			{`matches(country, country)`, "column 1: matches: pattern must be a string"},
+			{`matches(country, "[")`, "column 18: invalid pattern: error parsing regexp: missing closing ]: `[`"},
+			{`exists(po_box)`, "column 1: unknown function: exists"},
+			{`country "Wales"`, `column 9: unexpected string "Wales"`},
+		}
+
+		for _, test := range tests {
+			Convey("When parsing "+test.expr, func() {
+				_, err := parse(test.expr)
+
+				Convey("The error should identify the column and problem", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldEqual, test.want)
+				})
+			})
+		}
+	})
+}
+
+func TestUnitEvaluateRules(t *testing.T) {
+	Convey("Given a rules file and a tag map", t, func() {
+
+		tagMap, err := btd.LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		set, err := LoadRules("testdata/rules.yaml", tagMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When evaluating tag data satisfying every rule", func() {
+			failures := set.Evaluate(btd.TagData{
+				{"1000", "company_number", "0008", "AB123456"},
+				{"2001", "po_box", "0003", "123"},
+				{"2002", "postcode", "0007", "CF1 3UZ"},
+			})
+
+			Convey("No failures should be reported", func() {
+				So(failures, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When evaluating tag data failing several rules", func() {
+			failures := set.Evaluate(btd.TagData{
+				{"1000", "company_number", "0003", "123"},
+				{"2001", "po_box", "0003", "123"},
+				{"2003", "country", "0007", "England"},
+				{"2004", "welsh_name", "0003", "Cwm"},
+				{"3001", "officer", "0001", "a"},
+				{"3001", "officer", "0001", "b"},
+				{"3001", "officer", "0001", "c"},
+			})
+
+			Convey("A failure should be reported for each rule in order", func() {
+				So(failures, ShouldResemble, []*Failure{
+					{ID: "po-box-postcode", Severity: Error, Message: "A postcode is required when a PO box is given"},
+					{ID: "welsh-name-country", Severity: Warning, Message: "A Welsh name is only expected for companies in Wales"},
+					{ID: "company-number-format", Severity: Error, Message: `matches(company_number, "^[A-Z0-9]{8}$")`},
+					{ID: "officer-limit", Severity: Info, Message: "More than two officers"},
+				})
+			})
+		})
+	})
+}
+
+func TestUnitLoadRulesWithErrors(t *testing.T) {
+	Convey("Given a tag map", t, func() {
+
+		tagMap, err := btd.LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(t.TempDir(), "rules.yaml")
+
+		tests := []struct {
+			name    string
+			content string
+			want    string
+		}{
+			{"an unknown tag name", "rules:\n  - id: r1\n    expr: present(po_bx)\n", ": rule r1: expr: unknown tag name: po_bx"},
+			{"a syntax error", "rules:\n  - id: r1\n    when: po_box ==\n    expr: true\n", ": rule r1: when: column 10: unexpected end of expression"},
+			{"a missing expression", "rules:\n  - id: r1\n", ": rule r1: expr cannot be empty"},
+			{"a missing id", "rules:\n  - expr: true\n", ": rule 1: id cannot be empty"},
+			{"a duplicate id", "rules:\n  - id: r1\n    expr: true\n  - id: r1\n    expr: true\n", ": duplicate rule id: r1"},
+			{"an unknown severity", "rules:\n  - id: r1\n    severity: fatal\n    expr: true\n", ": severity must be one of [error warning info]: fatal"},
+		}
+
+		for _, test := range tests {
+			Convey("When loading a rules file with "+test.name, func() {
+				if err := os.WriteFile(path, []byte(test.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+
+				set, err := LoadRules(path, tagMap)
+
+				Convey("The rule set should be nil", func() {
+					So(set, ShouldBeNil)
+				})
+
+				Convey("The error should identify the problem", func() {
+					So(err.Error(), ShouldEqual, path+test.want)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Schema describes the values permitted for each tag of a transaction.
+type Schema struct {
+	// Tags holds the rule for each tag, keyed by XML tag name or tag id.
+	Tags map[string]*FieldRule `yaml:"tags"`
+
+	path string
+}
+
+// FieldRule constrains the value of a tag. Lengths are counted in
+// characters; zero means unconstrained.
+type FieldRule struct {
+	Type      string   `yaml:"type"` // string, numeric, alpha or alphanumeric
+	MinLength int      `yaml:"min-length"`
+	MaxLength int      `yaml:"max-length"`
+	Pattern   string   `yaml:"pattern"` // regular expression the value must match
+	Enum      []string `yaml:"enum"`    // permitted values
+	Required  bool     `yaml:"required"`
+
+	pattern *regexp.Regexp
+}
+
+// valueTypes holds the check for each supported value type.
+var valueTypes = map[string]func(rune) bool{
+	"string":       func(rune) bool { return true },
+	"numeric":      func(r rune) bool { return r >= '0' && r <= '9' },
+	"alpha":        unicode.IsLetter,
+	"alphanumeric": func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) },
+}
+
+// Violation is a tag value that does not satisfy the schema.
+type Violation struct {
+	Tag     string // XML tag name, or schema key for a missing required tag
+	ID      string // tag id; empty for a missing required tag
+	Offset  int    // offset of the tag within its transaction; -1 for a missing required tag
+	Rule    string // name of the rule violated
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Tag + ": " + v.Message
+}
+
+// LoadSchema loads the YAML schema file at path.
+func LoadSchema(path string) (*Schema, error) {
+	if len(path) == 0 {
+		return nil, errors.New("path cannot be empty")
+	}
+
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema file: %s", path)
+	}
+	defer fp.Close()
+
+	schema := &Schema{path: path}
+
+	decoder := yaml.NewDecoder(fp)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(schema); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for key, rule := range schema.Tags {
+		if rule == nil {
+			return nil, fmt.Errorf("%s: tag %s: rule cannot be empty", path, key)
+		}
+
+		if _, ok := valueTypes[rule.Type]; !ok && len(rule.Type) > 0 {
+			return nil, fmt.Errorf("%s: tag %s: unknown type: %s", path, key, rule.Type)
+		}
+
+		if rule.MaxLength > 0 && rule.MinLength > rule.MaxLength {
+			return nil, fmt.Errorf("%s: tag %s: min-length cannot exceed max-length", path, key)
+		}
+
+		if len(rule.Pattern) > 0 {
+			if rule.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("%s: tag %s: invalid pattern: %w", path, key, err)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// Path returns the path the schema was loaded from.
+func (s *Schema) Path() string {
+	return s.path
+}
+
+// Validate checks every tag of tx against the schema, returning the
+// violations found in the order the tags appear, followed by any required
+// tags that are missing.
+func (s *Schema) Validate(tx *Transaction) []*Violation {
+	var violations []*Violation
+
+	present := make(map[string]bool)
+
+	for _, tag := range tx.Tags {
+		key, rule := s.lookup(tag)
+		if rule == nil {
+			continue
+		}
+
+		present[key] = true
+
+		for _, message := range rule.check(tag.Value) {
+			violations = append(violations, &Violation{
+				Tag: tag.Name, ID: tag.ID, Offset: tag.Offset, Rule: message.rule,
+				Message: message.text,
+			})
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(s.Tags))
+	for _, key := range keys {
+		if s.Tags[key].Required && !present[key] {
+			violations = append(violations, &Violation{
+				Tag: key, Offset: -1, Rule: "required",
+				Message: "required tag is missing",
+			})
+		}
+	}
+
+	return violations
+}
+
+// lookup returns the rule for tag and the key it is held under, preferring
+// a rule for the tag's name over one for its id.
+func (s *Schema) lookup(tag Tag) (string, *FieldRule) {
+	if rule, ok := s.Tags[tag.Name]; ok {
+		return tag.Name, rule
+	}
+
+	for key, rule := range s.Tags {
+		if isNumeric(key) && normaliseID(key) == tag.key() {
+			return key, rule
+		}
+	}
+
+	return "", nil
+}
+
+type ruleMessage struct {
+	rule string
+	text string
+}
+
+// check returns a message for each constraint value does not satisfy.
+func (r *FieldRule) check(value string) []ruleMessage {
+	var messages []ruleMessage
+
+	length := utf8.RuneCountInString(value)
+
+	if valid, ok := valueTypes[r.Type]; ok && strings.IndexFunc(value, func(c rune) bool { return !valid(c) }) >= 0 {
+		messages = append(messages, ruleMessage{"type", fmt.Sprintf("value %q is not %s", value, r.Type)})
+	}
+
+	if length < r.MinLength {
+		messages = append(messages, ruleMessage{"min-length", fmt.Sprintf("value %q is shorter than %d characters", value, r.MinLength)})
+	}
+
+	if r.MaxLength > 0 && length > r.MaxLength {
+		messages = append(messages, ruleMessage{"max-length", fmt.Sprintf("value %q is longer than %d characters", value, r.MaxLength)})
+	}
+
+	if r.pattern != nil && !r.pattern.MatchString(value) {
+		messages = append(messages, ruleMessage{"pattern", fmt.Sprintf("value %q does not match pattern %s", value, r.Pattern)})
+	}
+
+	if len(r.Enum) > 0 && !slices.Contains(r.Enum, value) {
+		messages = append(messages, ruleMessage{"enum", fmt.Sprintf("value %q is not one of %s", value, strings.Join(r.Enum, ", "))})
+	}
+
+	return messages
+}
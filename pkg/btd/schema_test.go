@@ -0,0 +1,86 @@
+package btd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLoadSchemaWithInvalidRules(t *testing.T) {
+	Convey("Given schema files containing invalid rules", t, func() {
+
+		tests := map[string]string{
+			"tags:\n  one:\n    type: date\n":                       "tag one: unknown type: date",
+			"tags:\n  one:\n    pattern: '['\n":                     "tag one: invalid pattern: error parsing regexp: missing closing ]: `[`",
+			"tags:\n  one:\n    min-length: 5\n    max-length: 4\n": "tag one: min-length cannot exceed max-length",
+			"tags:\n  one:\n    maximum: 4\n":                       "yaml: unmarshal errors:\n  line 3: field maximum not found in type btd.FieldRule",
+		}
+
+		for input, message := range tests {
+			Convey("When loading a schema with the problem: "+message, func() {
+				path := filepath.Join(t.TempDir(), "schema.yaml")
+				So(os.WriteFile(path, []byte(input), 0o644), ShouldBeNil)
+
+				schema, err := LoadSchema(path)
+
+				Convey("The schema should be nil", func() {
+					So(schema, ShouldBeNil)
+				})
+
+				Convey("The error should describe the problem", func() {
+					So(err.Error(), ShouldEqual, path+": "+message)
+				})
+			})
+		}
+	})
+}
+
+func TestUnitValidate(t *testing.T) {
+	Convey("Given a schema and tag map", t, func() {
+
+		schema, err := LoadSchema("testdata/schema.yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When validating a transaction that satisfies the schema", func() {
+			tx, err := tagMap.ParseTransaction("00010008AB01234500020004abcd00030001Y0004000212" + "00050000")
+			So(err, ShouldBeNil)
+
+			violations := schema.Validate(tx)
+
+			Convey("No violations should be found", func() {
+				So(violations, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When validating a transaction violating the schema", func() {
+			tx, err := tagMap.ParseTransaction("00010008ab01234500020005abcde00030001X00040001a")
+			So(err, ShouldBeNil)
+
+			violations := schema.Validate(tx)
+
+			Convey("Each violation should be reported in order", func() {
+				So(violations, ShouldResemble, []*Violation{
+					{Tag: "one", ID: "0001", Offset: 0, Rule: "pattern", Message: `value "ab012345" does not match pattern ^[A-Z0-9]{8}$`},
+					{Tag: "two", ID: "0002", Offset: 16, Rule: "max-length", Message: `value "abcde" is longer than 4 characters`},
+					{Tag: "three", ID: "0003", Offset: 29, Rule: "enum", Message: `value "X" is not one of Y, N`},
+					{Tag: "four", ID: "0004", Offset: 38, Rule: "type", Message: `value "a" is not numeric`},
+					{Tag: "four", ID: "0004", Offset: 38, Rule: "min-length", Message: `value "a" is shorter than 2 characters`},
+					{Tag: "five", Offset: -1, Rule: "required", Message: "required tag is missing"},
+				})
+			})
+
+			Convey("The violations should describe the tag", func() {
+				So(violations[0].Error(), ShouldStartWith, "one: value")
+			})
+		})
+	})
+}
@@ -0,0 +1,428 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// EmbeddedTagMap is the path used to load the tag map built into the package,
+// as a last resort when no tag map file is available.
+const EmbeddedTagMap = "<embedded>"
+
+//go:embed defaults/tagmap.dat
+var embeddedTagMap []byte
+
+// TagDef is a single tag mapping declared in a tag map file.
+type TagDef struct {
+	ID          string   // tag id, as written in the tag map
+	Name        string   // XML tag name
+	Aliases     []string // alternative names accepted when looking up the tag by name
+	Type        string   // optional type of the tag value
+	Description string   // optional human-readable description
+	Layout      string   // layout of a date value, in the form accepted by time.Parse
+	Codes       []Code   // permitted values of an enumerated tag
+	Line        int      // line number of the mapping within the tag map file, or its position for JSON and TOML files
+	Source      string   // path of the tag map file the mapping was loaded from
+}
+
+// TagMapSyntaxError describes a line of a tag map file that could not be
+// parsed. Line is zero if the problem could not be attributed to a line.
+type TagMapSyntaxError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e *TagMapSyntaxError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+}
+
+func (e *TagMapSyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// TagMapLoader loads tag map files.
+type TagMapLoader struct {
+	// Strict causes Load to fail if linting the tag map reports any issues,
+	// rather than silently using the last mapping of a duplicated id.
+	Strict bool
+
+	// Dialect is used to check the width of ids when loading strictly; nil
+	// means the Standard dialect.
+	Dialect *Dialect
+
+	// Format is the format of the tag map file; FormatAuto detects the
+	// format from the file's extension.
+	Format TagMapFormat
+}
+
+// Load loads the tag maps at paths as layers, with each file adding to or
+// overriding the mappings and groups of the files before it. When loading
+// strictly, each file is linted independently and the error returned for a
+// file with lint issues wraps each *LintIssue.
+func (l *TagMapLoader) Load(paths ...string) (*tagMapData, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("path cannot be empty")
+	}
+
+	var files []*tagMapFile
+
+	for _, path := range paths {
+		file, err := l.loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	tagMap := newTagMap(files, paths)
+
+	if len(tagMap.tags) == 0 {
+		return nil, fmt.Errorf("no mappings in tag map file: %s", strings.Join(paths, ", "))
+	}
+
+	return tagMap, nil
+}
+
+// loadFile loads a single tag map file, or the embedded tag map, recording
+// it as the source of each mapping.
+func (l *TagMapLoader) loadFile(path string) (*tagMapFile, error) {
+	if len(path) == 0 {
+		return nil, errors.New("path cannot be empty")
+	}
+
+	var (
+		file *tagMapFile
+		err  error
+	)
+
+	if path == EmbeddedTagMap {
+		file, err = parseTagMap(bytes.NewReader(embeddedTagMap), path)
+	} else {
+		file, err = l.decodeFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range file.tags {
+		file.tags[i].Source = path
+	}
+
+	if l.Strict {
+		if issues := Lint(file.tags, path, l.Dialect); len(issues) > 0 {
+			errs := make([]error, len(issues))
+			for i, issue := range issues {
+				errs[i] = issue
+			}
+			return nil, errors.Join(errs...)
+		}
+	}
+
+	return file, nil
+}
+
+// decodeFile parses the tag map file at path.
+func (l *TagMapLoader) decodeFile(path string) (*tagMapFile, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read tag map file: %s", path)
+	}
+	defer fp.Close()
+
+	return decodeTagMap(fp, path, l.Format)
+}
+
+// tagMapFile holds the contents of a parsed tag map file.
+type tagMapFile struct {
+	tags   []TagDef
+	groups []GroupDef
+}
+
+// parseTagMap parses a tag map file. Each non-blank line contains either a tag
+// mapping or a group directive, optionally followed by a '#' comment:
+//
+//	<id> <name> [<type> [<description>...]] [type=<type>] [desc=<description>]
+//	            [alias=<name>[,<name>...]] [layout=<layout>] [code=<value>:<label>...]
+//	group <name> <start-id> <end-id>
+//	repeat <name> <start-id> [<member-id>...]
+//
+// The optional type and description columns may be given positionally, with
+// "-" as the type of a tag with a description but no type, or as attributes.
+// Attribute values containing spaces must be double-quoted.
+func parseTagMap(r io.Reader, path string) (*tagMapFile, error) {
+	file := &tagMapFile{}
+
+	s := bufio.NewScanner(r)
+	s.Split(bufio.ScanLines)
+
+	line := 0
+
+	for s.Scan() {
+		line++
+
+		syntaxError := func(err error) error {
+			return &TagMapSyntaxError{Path: path, Line: line, Err: err}
+		}
+
+		fields, err := splitTagMapLine(s.Text())
+		if err != nil {
+			return nil, syntaxError(err)
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		if isGroupDirective(fields[0]) {
+			def, err := parseGroupDef(fields)
+			if err != nil {
+				return nil, syntaxError(err)
+			}
+
+			file.groups = append(file.groups, def)
+			continue
+		}
+
+		def, err := parseTagDef(fields)
+		if err != nil {
+			return nil, syntaxError(err)
+		}
+
+		def.Line = line
+		file.tags = append(file.tags, def)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read tag map file: %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// parseTagDef parses the fields of a tag mapping.
+func parseTagDef(fields []string) (TagDef, error) {
+	if !isNumeric(fields[0]) {
+		return TagDef{}, fmt.Errorf("expected numeric id or directive: found %q", fields[0])
+	}
+
+	if len(fields) < 2 {
+		return TagDef{}, fmt.Errorf("missing name for id: %s", fields[0])
+	}
+
+	if !isIdentifier(fields[1]) {
+		return TagDef{}, fmt.Errorf("invalid name for id %s: %q", fields[0], fields[1])
+	}
+
+	def := TagDef{ID: fields[0], Name: fields[1]}
+	seen := make(map[string]bool)
+
+	// The type and description columns are positional, preceding any
+	// attributes; the description runs to the first attribute.
+	columns := fields[2:]
+	attrs := slices.IndexFunc(columns, isAttribute)
+	if attrs < 0 {
+		attrs = len(columns)
+	}
+
+	if attrs > 0 {
+		if columns[0] != noType {
+			if !isIdentifier(columns[0]) {
+				return TagDef{}, fmt.Errorf("invalid type: %q", columns[0])
+			}
+			def.Type = columns[0]
+			seen["type"] = true
+		}
+
+		if attrs > 1 {
+			def.Description = strings.Join(columns[1:attrs], " ")
+			seen["desc"] = true
+		}
+	}
+
+	for _, field := range columns[attrs:] {
+		if !isAttribute(field) {
+			return TagDef{}, fmt.Errorf("expected attribute of the form key=value: found %q", field)
+		}
+
+		key, value, _ := strings.Cut(field, "=")
+
+		if seen[key] && key != "alias" && key != "code" {
+			return TagDef{}, fmt.Errorf("duplicate attribute: %s", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "type":
+			if !isIdentifier(value) {
+				return TagDef{}, fmt.Errorf("invalid type: %q", value)
+			}
+			def.Type = value
+		case "desc":
+			def.Description = value
+		case "alias":
+			for _, alias := range strings.Split(value, ",") {
+				if !isIdentifier(alias) {
+					return TagDef{}, fmt.Errorf("invalid alias: %q", alias)
+				}
+				def.Aliases = append(def.Aliases, alias)
+			}
+		case "layout":
+			def.Layout = value
+		case "code":
+			code, label, ok := strings.Cut(value, ":")
+			if !ok || len(code) == 0 {
+				return TagDef{}, fmt.Errorf("invalid code: %q", value)
+			}
+			if slices.ContainsFunc(def.Codes, func(c Code) bool { return c.Value == code }) {
+				return TagDef{}, fmt.Errorf("duplicate code: %s", code)
+			}
+			def.Codes = append(def.Codes, Code{Value: code, Label: label})
+		default:
+			return TagDef{}, fmt.Errorf("unknown attribute: %s", key)
+		}
+	}
+
+	if err := checkDecoder(def); err != nil {
+		return TagDef{}, err
+	}
+
+	return def, nil
+}
+
+// noType is written in the type column of a tag mapping that has a
+// description but no type.
+const noType = "-"
+
+// isAttribute reports whether field of a tag mapping is a key=value attribute.
+func isAttribute(field string) bool {
+	key, _, ok := strings.Cut(field, "=")
+
+	return ok && isIdentifier(key)
+}
+
+// checkDecoder checks that the attributes needed to decode the values of a
+// tag are given, and only for the types that use them.
+func checkDecoder(def TagDef) error {
+	switch {
+	case def.Type == TypeDate && len(def.Layout) == 0:
+		return fmt.Errorf("missing layout for date id: %s", def.ID)
+	case def.Type == TypeEnum && len(def.Codes) == 0:
+		return fmt.Errorf("missing codes for enum id: %s", def.ID)
+	case def.Type != TypeDate && len(def.Layout) > 0:
+		return fmt.Errorf("layout requires type=date for id: %s", def.ID)
+	case def.Type != TypeEnum && len(def.Codes) > 0:
+		return fmt.Errorf("codes require type=enum for id: %s", def.ID)
+	}
+
+	return nil
+}
+
+// splitTagMapLine splits a line of a tag map file into whitespace-separated
+// fields, discarding any comment. Double-quoted text, in which \" and \\ may
+// be used to escape a quote or backslash, is kept within a single field.
+func splitTagMapLine(line string) ([]string, error) {
+	var (
+		fields  []string
+		field   strings.Builder
+		inField bool
+		quoted  bool
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inField = true
+		case quoted:
+			field.WriteRune(r)
+		case r == '#':
+			return appendField(fields, &field, inField), nil
+		case unicode.IsSpace(r):
+			fields = appendField(fields, &field, inField)
+			inField = false
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+
+	if quoted {
+		return nil, errors.New("unterminated quoted value")
+	}
+
+	return appendField(fields, &field, inField), nil
+}
+
+func appendField(fields []string, field *strings.Builder, inField bool) []string {
+	if inField {
+		fields = append(fields, field.String())
+		field.Reset()
+	}
+
+	return fields
+}
+
+// isIdentifier reports whether s is a valid tag name: a letter or underscore
+// followed by any number of letters, digits, underscores, hyphens or periods.
+func isIdentifier(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for i, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNumeric reports whether s consists solely of ASCII digits.
+func isNumeric(s string) bool {
+	return len(s) > 0 && !slices.ContainsFunc([]rune(s), func(r rune) bool { return r < '0' || r > '9' })
+}
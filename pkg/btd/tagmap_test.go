@@ -0,0 +1,213 @@
+package btd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitLoadTagMapWithRichGrammar(t *testing.T) {
+	Convey("Given a tag map using comments, attributes and names containing digits", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_rich.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When retrieving the tag definitions", func() {
+			tags := tagMap.Tags()
+
+			Convey("Every mapping should be loaded with its attributes and line number", func() {
+				So(tags, ShouldResemble, []TagDef{
+					{ID: "0001", Name: "company_number", Aliases: []string{"crn", "number"}, Type: "string", Description: "Company registration number", Line: 3, Source: "testdata/tagmap_rich.dat"},
+					{ID: "0002", Name: "address_line_1", Line: 4, Source: "testdata/tagmap_rich.dat"},
+					{ID: "0003", Name: "address_line_2", Description: `Second line of the "address"`, Line: 5, Source: "testdata/tagmap_rich.dat"},
+					{ID: "0004", Name: "xml.name-with_punctuation", Line: 6, Source: "testdata/tagmap_rich.dat"},
+				})
+			})
+
+			Convey("The group directive should be loaded", func() {
+				So(tagMap.Groups(), ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When retrieving the name of a tag whose name contains digits", func() {
+			name, err := tagMap.GetTagName("0003")
+
+			Convey("The name should not be truncated", func() {
+				So(name, ShouldEqual, "address_line_2")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When retrieving the id of a tag by its alias", func() {
+			id, err := tagMap.GetTagID("crn")
+
+			Convey("The id should be that of the aliased tag", func() {
+				So(id, ShouldEqual, "0001")
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestUnitParseTagMapWithPositionalColumns(t *testing.T) {
+	Convey("Given tag mappings with positional type and description columns", t, func() {
+
+		input := strings.Join([]string{
+			"0001 one  string  Company number",
+			"0002 two  -       Address line alias=addr",
+			"0003 three string",
+		}, "\n")
+
+		Convey("When parsing the tag map", func() {
+			file, err := parseTagMap(strings.NewReader(input), "tagmap.dat")
+			So(err, ShouldBeNil)
+
+			Convey("The type and description should be read from the columns", func() {
+				So(file.tags[0].Type, ShouldEqual, "string")
+				So(file.tags[0].Description, ShouldEqual, "Company number")
+			})
+
+			Convey("A type of - should mean no type, and attributes may follow", func() {
+				So(file.tags[1].Type, ShouldBeEmpty)
+				So(file.tags[1].Description, ShouldEqual, "Address line")
+				So(file.tags[1].Aliases, ShouldResemble, []string{"addr"})
+			})
+
+			Convey("The description column should be optional", func() {
+				So(file.tags[2].Type, ShouldEqual, "string")
+				So(file.tags[2].Description, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestUnitParseTagMapWithSyntaxErrors(t *testing.T) {
+	Convey("Given tag map files containing syntax errors", t, func() {
+
+		tests := []struct {
+			input string
+			line  int
+			err   string
+		}{
+			{"0001 one\ncompany_number\n", 2, `expected numeric id or directive: found "company_number"`},
+			{"0001\n", 1, "missing name for id: 0001"},
+			{"0001 2nd\n", 1, `invalid name for id 0001: "2nd"`},
+			{"0001 one alias=first extra\n", 1, `expected attribute of the form key=value: found "extra"`},
+			{"0001 one 2nd\n", 1, `invalid type: "2nd"`},
+			{"0001 one string type=int\n", 1, "duplicate attribute: type"},
+			{"0001 one string Company number desc=x\n", 1, "duplicate attribute: desc"},
+			{"0001 one colour=red\n", 1, "unknown attribute: colour"},
+			{"0001 one type=a type=b\n", 1, "duplicate attribute: type"},
+			{"\n\n0001 one desc=\"unterminated\n", 3, "unterminated quoted value"},
+			{"0001 one\nrepeat 0001 0002\n", 2, `invalid name in repeat directive: "0001"`},
+			{"0001 one type=date\n", 1, "missing layout for date id: 0001"},
+			{"0001 one type=enum\n", 1, "missing codes for enum id: 0001"},
+			{"0001 one layout=2006\n", 1, "layout requires type=date for id: 0001"},
+			{"0001 one type=int code=0:zero\n", 1, "codes require type=enum for id: 0001"},
+			{"0001 one type=enum code=zero\n", 1, `invalid code: "zero"`},
+			{"0001 one type=enum code=0:zero code=0:nil\n", 1, "duplicate code: 0"},
+		}
+
+		for _, test := range tests {
+			Convey("When parsing "+strings.TrimSpace(test.input), func() {
+				file, err := parseTagMap(strings.NewReader(test.input), "tagmap.dat")
+
+				Convey("The file should be nil", func() {
+					So(file, ShouldBeNil)
+				})
+
+				Convey("The error should identify the line and problem", func() {
+					var syntaxErr *TagMapSyntaxError
+					So(errors.As(err, &syntaxErr), ShouldBeTrue)
+					So(syntaxErr.Line, ShouldEqual, test.line)
+					So(syntaxErr.Err.Error(), ShouldEqual, test.err)
+					So(err.Error(), ShouldStartWith, "tagmap.dat:")
+				})
+			})
+		}
+	})
+}
+
+func TestUnitLoadLayeredTagMaps(t *testing.T) {
+	Convey("Given a base tag map and a local tag map overriding it", t, func() {
+
+		base, local := "testdata/tagmap.dat", "testdata/tagmap_local.dat"
+
+		tagMap, err := LoadTagMap(base, local)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When retrieving the name of an overridden id", func() {
+			name, err := tagMap.GetTagName("0002")
+
+			Convey("The name should be taken from the local tag map", func() {
+				So(name, ShouldEqual, "second")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When retrieving the id of an overridden name", func() {
+			_, err := tagMap.GetTagID("two")
+
+			Convey("The name should no longer be mapped", func() {
+				So(err.Error(), ShouldEqual, "unknown tag name: two")
+			})
+		})
+
+		Convey("When retrieving the tag definitions", func() {
+			tags := tagMap.Tags()
+
+			Convey("Overridden mappings should be replaced in place and new mappings added", func() {
+				So(tags, ShouldHaveLength, 11)
+				So(tags[1], ShouldResemble, TagDef{ID: "0002", Name: "second", Line: 2, Source: local})
+				So(tags[10], ShouldResemble, TagDef{ID: "0011", Name: "eleven", Line: 3, Source: local})
+			})
+
+			Convey("Other mappings should be taken from the base tag map", func() {
+				So(tags[0].Source, ShouldEqual, base)
+			})
+		})
+
+		Convey("When retrieving the files the tag map was loaded from", func() {
+			paths := tagMap.LoadedFromFile()
+
+			Convey("Every file should be listed in order", func() {
+				So(paths, ShouldResemble, []string{base, local})
+			})
+		})
+	})
+}
+
+func TestUnitLoadEmbeddedTagMap(t *testing.T) {
+	Convey("Given the path of the embedded tag map", t, func() {
+
+		Convey("When loading the tag map", func() {
+			tagMap, err := LoadTagMap(EmbeddedTagMap)
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The tag map should contain the chtuxgw mappings", func() {
+				name, err := tagMap.GetTagName("7004")
+				So(name, ShouldEqual, "delivaddr")
+				So(err, ShouldBeNil)
+			})
+
+			Convey("The only lint issue should be the name chtuxgw shares between ids", func() {
+				issues := Lint(tagMap.Tags(), EmbeddedTagMap, nil)
+				So(len(issues), ShouldEqual, 1)
+				So(issues[0].Error(), ShouldContainSubstring, "name srchaddr is already used for id 2007")
+			})
+
+			Convey("The source should be the embedded tag map", func() {
+				So(tagMap.LoadedFromFile(), ShouldResemble, []string{EmbeddedTagMap})
+			})
+		})
+	})
+}
@@ -0,0 +1,136 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"iter"
+	"strings"
+)
+
+// Tag is a single tag parsed from business transaction data.
+type Tag struct {
+	ID             string   // tag id, as it appeared in the data
+	Name           string   // XML tag name from the tag map
+	DeclaredLength int      // value of the length field
+	Value          string   // tag data
+	Offset         int      // offset of the tag within its transaction, in characters or bytes as counted by the decoder
+	Dialect        *Dialect // dialect of the data the tag was parsed from; Standard if nil
+	Decoded        any      // value decoded according to the tag's type in the tag map; nil if the type has no decoder
+	DecodeErr      error    // problem decoding the value, if any
+
+	lengthField string // raw length field, if parsed from data
+}
+
+// LengthField returns the length field of the tag as it appeared in the data,
+// or the declared length formatted using the tag's dialect if the tag was not
+// parsed from data.
+func (t Tag) LengthField() string {
+	if len(t.lengthField) > 0 {
+		return t.lengthField
+	}
+
+	dialect := t.Dialect
+	if dialect == nil {
+		dialect = Standard
+	}
+
+	return dialect.formatLength(uint64(max(t.DeclaredLength, 0)))
+}
+
+// Transaction is a single parsed business transaction.
+type Transaction struct {
+	Line     int // line number of the transaction within its input
+	Tags     []Tag
+	Root     *Group        // tags arranged into the groups declared in the tag map
+	Type     *TypeMatch    // detected transaction type, if transaction types were given to the decoder
+	Metadata []LogField    // values captured from the log line the transaction was extracted from
+	Errors   []*ParseError // problems found when decoding leniently
+	Warnings []*ParseError // unknown tag ids reported under UnknownTagWarn
+}
+
+// Len returns the number of tags in the transaction.
+func (t *Transaction) Len() int {
+	return len(t.Tags)
+}
+
+// Get returns the first tag with the given XML tag name.
+func (t *Transaction) Get(name string) (Tag, bool) {
+	for _, tag := range t.Tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+
+	return Tag{}, false
+}
+
+// GetByID returns the first tag with the given tag id. Ids are compared
+// without their padding, so "10" and "0010" refer to the same tag.
+func (t *Transaction) GetByID(id string) (Tag, bool) {
+	id = normaliseID(strings.TrimSpace(id))
+
+	for _, tag := range t.Tags {
+		if tag.key() == id {
+			return tag, true
+		}
+	}
+
+	return Tag{}, false
+}
+
+// All returns every tag with the given XML tag name, in the order they
+// appear in the transaction.
+func (t *Transaction) All(name string) []Tag {
+	var tags []Tag
+
+	for _, tag := range t.Tags {
+		if tag.Name == name {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// Iter returns an iterator over the index and value of each tag in the
+// transaction.
+func (t *Transaction) Iter() iter.Seq2[int, Tag] {
+	return func(yield func(int, Tag) bool) {
+		for i, tag := range t.Tags {
+			if !yield(i, tag) {
+				return
+			}
+		}
+	}
+}
+
+// TagData returns the transaction in the row format accepted by Renderer
+// implementations.
+func (t *Transaction) TagData() TagData {
+	var tagData TagData
+
+	for _, tag := range t.Tags {
+		tagData = append(tagData, []string{tag.ID, tag.Name, tag.LengthField(), tag.Value})
+	}
+
+	return tagData
+}
@@ -0,0 +1,133 @@
+package btd
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitTransactionLookups(t *testing.T) {
+	Convey("Given a parsed transaction containing a repeated tag", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tx, err := tagMap.ParseTransaction("00010004abcd00020001x00010002ef")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When getting a tag by name", func() {
+			tag, ok := tx.Get("one")
+
+			Convey("The first matching tag should be returned", func() {
+				So(ok, ShouldBeTrue)
+				So(tag, ShouldResemble, Tag{
+					ID:             "0001",
+					Name:           "one",
+					DeclaredLength: 4,
+					Value:          "abcd",
+					Offset:         0,
+					Dialect:        Standard,
+					lengthField:    "0004",
+				})
+			})
+		})
+
+		Convey("When getting a tag by id", func() {
+			tag, ok := tx.GetByID("0002")
+
+			Convey("The matching tag should be returned", func() {
+				So(ok, ShouldBeTrue)
+				So(tag.Name, ShouldEqual, "two")
+				So(tag.Offset, ShouldEqual, 12)
+			})
+		})
+
+		Convey("When getting a tag by an unpadded id", func() {
+			tag, ok := tx.GetByID("2")
+
+			Convey("The matching tag should be returned", func() {
+				So(ok, ShouldBeTrue)
+				So(tag.Name, ShouldEqual, "two")
+			})
+		})
+
+		Convey("When getting a tag by an id that is not present", func() {
+			_, ok := tx.GetByID("0003")
+
+			Convey("No tag should be returned", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("When getting a tag that is not present", func() {
+			_, ok := tx.Get("three")
+
+			Convey("No tag should be returned", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("When getting all tags by name", func() {
+			tags := tx.All("one")
+
+			Convey("Every matching tag should be returned in order", func() {
+				So(len(tags), ShouldEqual, 2)
+				So(tags[0].Value, ShouldEqual, "abcd")
+				So(tags[1].Value, ShouldEqual, "ef")
+			})
+		})
+
+		Convey("When iterating over the transaction", func() {
+			var ids []string
+			for _, tag := range tx.Iter() {
+				ids = append(ids, tag.ID)
+			}
+
+			Convey("Every tag should be visited in order", func() {
+				So(ids, ShouldResemble, []string{"0001", "0002", "0001"})
+				So(tx.Len(), ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestUnitTransactionTagDataAdapter(t *testing.T) {
+	Convey("Given a transaction built from tags that were not parsed from data", t, func() {
+
+		tx := &Transaction{Tags: []Tag{{ID: "0001", Name: "one", DeclaredLength: 4, Value: "abcd"}}}
+
+		Convey("When converting the transaction to tag data", func() {
+			tagData := tx.TagData()
+
+			Convey("The rows should contain a zero-padded length field", func() {
+				So(tagData, ShouldResemble, TagData{{"0001", "one", "0004", "abcd"}})
+			})
+		})
+	})
+}
+
+func TestUnitTagLengthField(t *testing.T) {
+	Convey("Given tags that were not parsed from data", t, func() {
+
+		Convey("When formatting the length field of a tag without a dialect", func() {
+			field := Tag{DeclaredLength: 26}.LengthField()
+
+			Convey("The length should be formatted using the standard dialect", func() {
+				So(field, ShouldEqual, "0026")
+			})
+		})
+
+		Convey("When formatting the length field of a tag with a dialect", func() {
+			dialect := &Dialect{Name: "hex", IDWidth: 4, LengthWidth: 3, LengthBase: 16, Padding: ' '}
+			field := Tag{DeclaredLength: 26, Dialect: dialect}.LengthField()
+
+			Convey("The length should be formatted using the dialect's width, base and padding", func() {
+				So(field, ShouldEqual, " 1A")
+			})
+		})
+	})
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// TransactionType describes the tags making up a kind of filing transaction.
+// Tags are referred to by XML tag name or tag id.
+type TransactionType struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Match       map[string]string `yaml:"match"`    // tag values identifying the transaction type
+	Required    []string          `yaml:"required"` // tags that must be present
+	Optional    []string          `yaml:"optional"` // tags that may be present
+
+	match    map[string]string // Match keyed by normalised id
+	required []string          // normalised ids of the required tags
+	allowed  map[string]bool   // normalised ids of the required and optional tags
+}
+
+// TransactionTypes is a set of transaction type definitions.
+type TransactionTypes struct {
+	Types []*TransactionType `yaml:"types"`
+
+	path string
+}
+
+// TypeMatch is the transaction type detected for a transaction.
+type TypeMatch struct {
+	Type       *TransactionType
+	Missing    []string // required tags absent from the transaction, as written in the definition
+	Unexpected []Tag    // tags that are neither required nor optional
+}
+
+// LoadTransactionTypes loads the YAML transaction type definitions file at
+// path, resolving the tags it refers to using tagMap.
+func LoadTransactionTypes(path string, tagMap TagMap) (*TransactionTypes, error) {
+	if len(path) == 0 {
+		return nil, errors.New("path cannot be empty")
+	}
+
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read transaction types file: %s", path)
+	}
+	defer fp.Close()
+
+	types := &TransactionTypes{path: path}
+
+	decoder := yaml.NewDecoder(fp)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(types); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, t := range types.Types {
+		if len(t.Name) == 0 {
+			return nil, fmt.Errorf("%s: transaction type name cannot be empty", path)
+		}
+
+		if err := t.resolve(tagMap); err != nil {
+			return nil, fmt.Errorf("%s: transaction type %s: %w", path, t.Name, err)
+		}
+	}
+
+	return types, nil
+}
+
+// Path returns the path the definitions were loaded from.
+func (t *TransactionTypes) Path() string {
+	return t.path
+}
+
+// resolve looks up the ids of the tags referred to by the transaction type.
+func (t *TransactionType) resolve(tagMap TagMap) error {
+	t.match = make(map[string]string)
+	t.allowed = make(map[string]bool)
+
+	for ref, value := range t.Match {
+		id, err := resolveTagRef(tagMap, ref)
+		if err != nil {
+			return err
+		}
+		t.match[id] = value
+	}
+
+	for _, ref := range t.Required {
+		id, err := resolveTagRef(tagMap, ref)
+		if err != nil {
+			return err
+		}
+		t.required = append(t.required, id)
+		t.allowed[id] = true
+	}
+
+	for _, ref := range t.Optional {
+		id, err := resolveTagRef(tagMap, ref)
+		if err != nil {
+			return err
+		}
+		t.allowed[id] = true
+	}
+
+	return nil
+}
+
+// resolveTagRef returns the normalised id of the tag referred to by ref,
+// which may be an XML tag name or tag id.
+func resolveTagRef(tagMap TagMap, ref string) (string, error) {
+	if isNumeric(ref) {
+		if _, ok := tagMap.Lookup(ref); !ok {
+			return "", fmt.Errorf("unknown id: %s", ref)
+		}
+		return normaliseID(ref), nil
+	}
+
+	id, err := tagMap.GetTagID(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return normaliseID(id), nil
+}
+
+// Detect returns the transaction type that best describes tx, or nil if no
+// type matches. Of the types whose match values are satisfied and which
+// share at least one tag with tx, the type with the fewest missing required
+// tags is chosen, then the fewest unexpected tags, then the most required
+// tags; any remaining tie is resolved in favour of the first defined.
+func (t *TransactionTypes) Detect(tx *Transaction) *TypeMatch {
+	var best *TypeMatch
+
+	for _, typ := range t.Types {
+		match := typ.compare(tx)
+		if match == nil {
+			continue
+		}
+
+		if best == nil || match.better(best) {
+			best = match
+		}
+	}
+
+	return best
+}
+
+// compare returns how tx matches the transaction type, or nil if it cannot
+// be of this type.
+func (t *TransactionType) compare(tx *Transaction) *TypeMatch {
+	present := make(map[string]string)
+	for _, tag := range tx.Tags {
+		if _, ok := present[tag.key()]; !ok {
+			present[tag.key()] = tag.Value
+		}
+	}
+
+	for id, value := range t.match {
+		if present[id] != value {
+			return nil
+		}
+	}
+
+	match := &TypeMatch{Type: t}
+
+	for i, id := range t.required {
+		if _, ok := present[id]; !ok {
+			match.Missing = append(match.Missing, t.Required[i])
+		}
+	}
+
+	for _, tag := range tx.Tags {
+		if !t.allowed[tag.key()] {
+			match.Unexpected = append(match.Unexpected, tag)
+		}
+	}
+
+	if len(match.Unexpected) == len(tx.Tags) {
+		return nil
+	}
+
+	return match
+}
+
+// better reports whether m describes its transaction better than other.
+func (m *TypeMatch) better(other *TypeMatch) bool {
+	if len(m.Missing) != len(other.Missing) {
+		return len(m.Missing) < len(other.Missing)
+	}
+
+	if len(m.Unexpected) != len(other.Unexpected) {
+		return len(m.Unexpected) < len(other.Unexpected)
+	}
+
+	return len(m.Type.required) > len(other.Type.required)
+}
+
+// Complete reports whether every required tag is present and no unexpected
+// tags were found.
+func (m *TypeMatch) Complete() bool {
+	return len(m.Missing) == 0 && len(m.Unexpected) == 0
+}
+
+// String returns the description of the transaction type, followed by its
+// name.
+func (t *TransactionType) String() string {
+	if len(t.Description) == 0 {
+		return t.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", t.Description, t.Name)
+}
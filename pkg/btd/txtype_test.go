@@ -0,0 +1,121 @@
+package btd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// detectType decodes data and returns the type detected using types.
+func detectType(t *testing.T, tagMap TagMap, types *TransactionTypes, data string) *TypeMatch {
+	d := NewDecoder(strings.NewReader(data), tagMap)
+	d.Types = types
+
+	tx, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tx.Type
+}
+
+func TestUnitDetectTransactionType(t *testing.T) {
+	Convey("Given a tag map and transaction type definitions", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		types, err := LoadTransactionTypes("testdata/txtypes.yaml", tagMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When detecting the type of a complete transaction", func() {
+			match := detectType(t, tagMap, types, "00010004AD0100020001b00030001c")
+
+			Convey("The type with the matching tag value should be detected", func() {
+				So(match.Type.Name, ShouldEqual, "address-change")
+				So(match.Type.String(), ShouldEqual, "Change of address (address-change)")
+			})
+
+			Convey("The match should be complete", func() {
+				So(match.Complete(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When detecting the type of a transaction with missing and unexpected tags", func() {
+			match := detectType(t, tagMap, types, "00010004AP0100060001f00090001i")
+
+			Convey("The type with the matching tag value should be detected", func() {
+				So(match.Type.Name, ShouldEqual, "officer-appointment")
+			})
+
+			Convey("The missing required tags should be reported as written in the definition", func() {
+				So(match.Missing, ShouldResemble, []string{"five"})
+			})
+
+			Convey("The unexpected tags should be reported", func() {
+				So(match.Unexpected, ShouldHaveLength, 1)
+				So(match.Unexpected[0].Name, ShouldEqual, "nine")
+				So(match.Complete(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When detecting the type of a transaction satisfying two types equally", func() {
+			match := detectType(t, tagMap, types, "00080001h00090001i")
+
+			Convey("The type with more required tags should be detected", func() {
+				So(match.Type.Name, ShouldEqual, "confirmation")
+			})
+		})
+
+		Convey("When detecting the type of a transaction missing a tag required by one type", func() {
+			match := detectType(t, tagMap, types, "00080001h00100001j")
+
+			Convey("The type with fewer missing tags should be detected", func() {
+				So(match.Type.Name, ShouldEqual, "confirmation-with-notes")
+				So(match.Missing, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When detecting the type of a transaction whose match value differs", func() {
+			match := detectType(t, tagMap, types, "00010004XX0100020001b00030001c")
+
+			Convey("No type should be detected", func() {
+				So(match, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestUnitLoadTransactionTypesWithUnknownTag(t *testing.T) {
+	Convey("Given transaction type definitions referring to a tag missing from the tag map", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(t.TempDir(), "txtypes.yaml")
+		if err := os.WriteFile(path, []byte("types:\n  - name: broken\n    required: [one, eleven]\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When loading the definitions", func() {
+			types, err := LoadTransactionTypes(path, tagMap)
+
+			Convey("The definitions should be nil", func() {
+				So(types, ShouldBeNil)
+			})
+
+			Convey("The error should identify the type and tag", func() {
+				So(err.Error(), ShouldEqual, path+": transaction type broken: unknown tag name: eleven")
+			})
+		})
+	})
+}
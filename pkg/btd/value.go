@@ -0,0 +1,109 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package btd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Types of tag value with a decoder. Tags of any other type are not decoded.
+const (
+	TypeDate = "date" // a date or time in the tag's layout, decoded as a time.Time
+	TypeInt  = "int"  // a decimal integer, decoded as an int64
+	TypeBool = "bool" // a flag such as 1/0 or Y/N, decoded as a bool
+	TypeEnum = "enum" // a code from the tag's code list, decoded as a Code
+)
+
+// Code is a permitted value of an enumerated tag and its meaning.
+type Code struct {
+	Value string `json:"value" yaml:"value" toml:"value"`
+	Label string `json:"label" yaml:"label" toml:"label"`
+}
+
+var (
+	trueValues  = []string{"1", "y", "yes", "t", "true"}
+	falseValues = []string{"0", "n", "no", "f", "false"}
+)
+
+// DecodeValue interprets value according to the type of the tag. It returns
+// nil if the tag's type has no decoder.
+func (d TagDef) DecodeValue(value string) (any, error) {
+	switch d.Type {
+	case TypeDate:
+		t, err := time.Parse(d.Layout, strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date for layout %s: %q", d.Layout, value)
+		}
+		return t, nil
+
+	case TypeInt:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int: %q", value)
+		}
+		return n, nil
+
+	case TypeBool:
+		v := strings.ToLower(strings.TrimSpace(value))
+		for i := range trueValues {
+			switch v {
+			case trueValues[i]:
+				return true, nil
+			case falseValues[i]:
+				return false, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid bool: %q", value)
+
+	case TypeEnum:
+		for _, code := range d.Codes {
+			if code.Value == value {
+				return code, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown code: %q", value)
+	}
+
+	return nil, nil
+}
+
+// FormatDecoded returns the display form of a value returned by
+// TagDef.DecodeValue. Dates are formatted in ISO 8601 form, including the
+// time of day only if it is set.
+func FormatDecoded(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format(time.DateOnly)
+		}
+		return v.Format(time.DateTime)
+	case Code:
+		return v.Label
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,84 @@
+package btd
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitDecodeValue(t *testing.T) {
+	Convey("Given a tag map declaring typed tags", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_typed.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			id      string
+			value   string
+			want    any
+			display string
+			err     string
+		}{
+			{"7000", "AD01", nil, "", ""},
+			{"7004", "22/11/33", time.Date(2033, 11, 22, 0, 0, 0, 0, time.UTC), "2033-11-22", ""},
+			{"7004", "11/22/33", nil, "", `invalid date for layout 02/01/06: "11/22/33"`},
+			{"7009", "0012", int64(12), "12", ""},
+			{"7009", "1A", nil, "", `invalid int: "1A"`},
+			{"7022", "Y", true, "true", ""},
+			{"7022", "0", false, "false", ""},
+			{"7022", "X", nil, "", `invalid bool: "X"`},
+			{"7023", "0", Code{Value: "0", Label: "Electronic filing"}, "Electronic filing", ""},
+			{"7023", "2", nil, "", `unknown code: "2"`},
+		}
+
+		for _, test := range tests {
+			Convey("When decoding "+test.value+" for tag "+test.id, func() {
+				def, ok := tagMap.Lookup(test.id)
+				So(ok, ShouldBeTrue)
+
+				v, err := def.DecodeValue(test.value)
+
+				Convey("The decoded value and its display form should be correct", func() {
+					So(v, ShouldResemble, test.want)
+					So(FormatDecoded(v), ShouldEqual, test.display)
+				})
+
+				Convey("Any error should describe the problem", func() {
+					if len(test.err) == 0 {
+						So(err, ShouldBeNil)
+					} else {
+						So(err.Error(), ShouldEqual, test.err)
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestUnitParseTransactionWithTypedTags(t *testing.T) {
+	Convey("Given a tag map declaring typed tags", t, func() {
+
+		tagMap, err := LoadTagMap("testdata/tagmap_typed.dat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When parsing a transaction", func() {
+			tx, err := tagMap.ParseTransaction("70000004AD017004000822/11/33702300011")
+
+			Convey("The error should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Each tag should carry its decoded value", func() {
+				So(tx.Tags[0].Decoded, ShouldBeNil)
+				So(FormatDecoded(tx.Tags[1].Decoded), ShouldEqual, "2033-11-22")
+				So(tx.Tags[2].Decoded, ShouldResemble, Code{Value: "1", Label: "Paper filing"})
+				So(tx.Tags[2].DecodeErr, ShouldBeNil)
+			})
+		})
+	})
+}
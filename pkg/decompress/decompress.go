@@ -0,0 +1,131 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package decompress detects compressed data from its magic bytes and
+// decompresses it as it is read.
+package decompress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Format is a compression format recognised by NewReader.
+type Format int
+
+const (
+	// None means the data is not compressed.
+	None Format = iota
+	Gzip
+	Bzip2
+	Zstd
+	Xz
+)
+
+var formatNames = []string{"none", "gzip", "bzip2", "zstd", "xz"}
+
+func (f Format) String() string {
+	if int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+
+	return "unknown"
+}
+
+// magic holds the bytes each compressed format begins with.
+var magic = []struct {
+	format Format
+	bytes  []byte
+}{
+	{Gzip, []byte{0x1f, 0x8b}},
+	{Bzip2, []byte("BZh")},
+	{Zstd, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{Xz, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// Detect returns the compression format of data from its leading bytes.
+func Detect(data []byte) Format {
+	for _, m := range magic {
+		if !bytes.HasPrefix(data, m.bytes) {
+			continue
+		}
+
+		// The bzip2 magic is followed by a block size of 1 to 9, checked so
+		// that text beginning "BZh" is not mistaken for compressed data.
+		if m.format == Bzip2 && (len(data) < 4 || data[3] < '1' || data[3] > '9') {
+			continue
+		}
+
+		return m.format
+	}
+
+	return None
+}
+
+// NewReader returns a reader of the data read from r, decompressed if it
+// begins with the magic bytes of a supported format, and the format detected.
+// Closing the reader releases the decompressor but does not close r.
+func NewReader(r io.Reader) (io.ReadCloser, Format, error) {
+	br := bufio.NewReader(r)
+
+	// A short read means the data is too small to be compressed, and any
+	// error is returned by the first Read.
+	header, _ := br.Peek(6)
+
+	format := Detect(header)
+
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+
+	switch format {
+	case Gzip:
+		rc, err = gzip.NewReader(br)
+	case Bzip2:
+		rc = io.NopCloser(bzip2.NewReader(br))
+	case Zstd:
+		var d *zstd.Decoder
+		if d, err = zstd.NewReader(br); err == nil {
+			rc = d.IOReadCloser()
+		}
+	case Xz:
+		var xr *xz.Reader
+		if xr, err = xz.NewReader(br); err == nil {
+			rc = io.NopCloser(xr)
+		}
+	default:
+		rc = io.NopCloser(br)
+	}
+
+	if err != nil {
+		return nil, format, fmt.Errorf("%s: %w", format, err)
+	}
+
+	return rc, format, nil
+}
@@ -0,0 +1,90 @@
+package decompress
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitNewReader(t *testing.T) {
+	Convey("Given data compressed in each supported format", t, func() {
+
+		want, err := os.ReadFile("testdata/data.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tc := range []struct {
+			path   string
+			format Format
+		}{
+			{"testdata/data.txt", None},
+			{"testdata/data.txt.gz", Gzip},
+			{"testdata/data.txt.bz2", Bzip2},
+			{"testdata/data.txt.zst", Zstd},
+			{"testdata/data.txt.xz", Xz},
+		} {
+			Convey("When reading "+tc.path, func() {
+				file, err := os.Open(tc.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer file.Close()
+
+				r, format, err := NewReader(file)
+				So(err, ShouldBeNil)
+				defer r.Close()
+
+				got, err := io.ReadAll(r)
+
+				Convey("Then the format should be detected and the data decompressed", func() {
+					So(format, ShouldEqual, tc.format)
+					So(err, ShouldBeNil)
+					So(string(got), ShouldEqual, string(want))
+				})
+			})
+		}
+	})
+
+	Convey("Given data shorter than any magic bytes", t, func() {
+
+		Convey("When reading the data", func() {
+			r, format, err := NewReader(strings.NewReader("0"))
+			So(err, ShouldBeNil)
+
+			got, _ := io.ReadAll(r)
+
+			Convey("Then it should be read unchanged", func() {
+				So(format, ShouldEqual, None)
+				So(string(got), ShouldEqual, "0")
+			})
+		})
+	})
+
+	Convey("Given data with a gzip header that is corrupt", t, func() {
+
+		Convey("When reading the data", func() {
+			_, _, err := NewReader(strings.NewReader("\x1f\x8bxxxxxxxxxx"))
+
+			Convey("Then an error naming the format should be returned", func() {
+				So(err.Error(), ShouldStartWith, "gzip: ")
+			})
+		})
+	})
+}
+
+func TestUnitDetect(t *testing.T) {
+	Convey("Given text beginning with the bzip2 magic bytes", t, func() {
+
+		Convey("When detecting the format", func() {
+			format := Detect([]byte("BZh0001"))
+
+			Convey("Then it should not be mistaken for compressed data", func() {
+				So(format, ShouldEqual, None)
+			})
+		})
+	})
+}
@@ -0,0 +1,203 @@
+/*
+Copyright © 2026 Companies House (Crown Copyright)
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+// Package follow reads a file as it grows, in the manner of tail -f.
+package follow
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// DefaultInterval is the interval at which a file is polled for changes
+// unless Reader.Interval is set.
+const DefaultInterval = 500 * time.Millisecond
+
+// Event is a change to a followed file that causes it to be read again from
+// the start.
+type Event int
+
+const (
+	// Truncated means the file became shorter than the data already read.
+	Truncated Event = iota + 1
+	// Rotated means the path now refers to a different file, such as when a
+	// log is renamed and a new file created in its place.
+	Rotated
+)
+
+var eventNames = []string{"", "truncated", "rotated"}
+
+func (e Event) String() string {
+	if int(e) < len(eventNames) {
+		return eventNames[e]
+	}
+
+	return "unknown"
+}
+
+// Reader reads a file, waiting for more data to be appended when the end of
+// the file is reached rather than returning io.EOF. Read returns io.EOF once
+// the context is done, or when the file is truncated or rotated, after which
+// Next continues reading from the start of the file.
+type Reader struct {
+	// Interval is the interval at which the file is polled for changes.
+	Interval time.Duration
+
+	// Notify, if set, is called when the file is read again from the start.
+	Notify func(Event)
+
+	ctx     context.Context
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	pending Event
+}
+
+// Open opens the file at path for following until ctx is done.
+func Open(ctx context.Context, path string) (*Reader, error) {
+	file, info, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reader{
+		Interval: DefaultInterval,
+		ctx:      ctx,
+		path:     path,
+		file:     file,
+		info:     info,
+	}, nil
+}
+
+func open(path string) (*os.File, os.FileInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, info, nil
+}
+
+// Read reads data from the file, blocking until data is available.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.pending != 0 || r.ctx.Err() != nil {
+		return 0, io.EOF
+	}
+
+	for {
+		n, err := r.file.Read(p)
+		if n > 0 {
+			r.offset += int64(n)
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		if r.pending = r.changed(); r.pending != 0 {
+			return 0, io.EOF
+		}
+
+		if !r.wait() {
+			return 0, io.EOF
+		}
+	}
+}
+
+// Next prepares to read the file again from the start after Read returned
+// io.EOF because the file was truncated or rotated. It reports false if
+// following has stopped.
+func (r *Reader) Next() bool {
+	if r.pending == 0 || r.ctx.Err() != nil {
+		return false
+	}
+
+	switch r.pending {
+	case Truncated:
+		if _, err := r.file.Seek(0, io.SeekStart); err != nil {
+			return false
+		}
+	case Rotated:
+		for {
+			file, info, err := open(r.path)
+			if err == nil {
+				r.file.Close()
+				r.file, r.info = file, info
+				break
+			}
+
+			if !r.wait() {
+				return false
+			}
+		}
+	}
+
+	if r.Notify != nil {
+		r.Notify(r.pending)
+	}
+
+	r.offset = 0
+	r.pending = 0
+
+	return true
+}
+
+// Close closes the file.
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+// changed returns the change made to the file since it was opened, if any.
+// A path that no longer exists is assumed to be about to be replaced.
+func (r *Reader) changed() Event {
+	if info, err := os.Stat(r.path); err == nil && !os.SameFile(info, r.info) {
+		return Rotated
+	}
+
+	if info, err := r.file.Stat(); err == nil && info.Size() < r.offset {
+		return Truncated
+	}
+
+	return 0
+}
+
+// wait waits for the poll interval to pass, reporting false if the context
+// is done first.
+func (r *Reader) wait() bool {
+	timer := time.NewTimer(r.Interval)
+	defer timer.Stop()
+
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
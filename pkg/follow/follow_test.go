@@ -0,0 +1,162 @@
+package follow
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// appendFile appends data to the file at path.
+func appendFile(t *testing.T, path, data string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// openTest opens path for following with a short poll interval, returning a
+// line reader and a function stopping the follow.
+func openTest(t *testing.T, path string) (*Reader, *bufio.Reader, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+	r, err := Open(ctx, path)
+	if err != nil {
+		cancel()
+		t.Fatal(err)
+	}
+	r.Interval = 5 * time.Millisecond
+
+	return r, bufio.NewReader(r), cancel
+}
+
+func TestUnitFollowAppendedLines(t *testing.T) {
+	Convey("Given a followed file", t, func() {
+
+		path := filepath.Join(t.TempDir(), "data.txt")
+		appendFile(t, path, "first\n")
+
+		r, lines, cancel := openTest(t, path)
+		defer cancel()
+		defer r.Close()
+
+		Convey("When lines are appended after the end is reached", func() {
+			first, _ := lines.ReadString('\n')
+
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				appendFile(t, path, "second\n")
+			}()
+
+			second, err := lines.ReadString('\n')
+
+			Convey("The appended lines should be read", func() {
+				So(first, ShouldEqual, "first\n")
+				So(second, ShouldEqual, "second\n")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When the context is done", func() {
+			lines.ReadString('\n')
+			cancel()
+
+			_, err := lines.ReadString('\n')
+
+			Convey("Reading should stop", func() {
+				So(err, ShouldEqual, io.EOF)
+				So(r.Next(), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestUnitFollowTruncatedFile(t *testing.T) {
+	Convey("Given a followed file that is truncated", t, func() {
+
+		path := filepath.Join(t.TempDir(), "data.txt")
+		appendFile(t, path, "first line\n")
+
+		r, lines, cancel := openTest(t, path)
+		defer cancel()
+		defer r.Close()
+
+		var events []Event
+		r.Notify = func(e Event) { events = append(events, e) }
+
+		lines.ReadString('\n')
+
+		if err := os.WriteFile(path, []byte("new\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		Convey("When reading past the end of the old content", func() {
+			_, err := lines.ReadString('\n')
+
+			Convey("io.EOF should be returned", func() {
+				So(err, ShouldEqual, io.EOF)
+			})
+
+			Convey("And continuing with the next content", func() {
+				So(r.Next(), ShouldBeTrue)
+				line, err := bufio.NewReader(r).ReadString('\n')
+
+				Convey("The file should be read from the start", func() {
+					So(line, ShouldEqual, "new\n")
+					So(err, ShouldBeNil)
+					So(events, ShouldResemble, []Event{Truncated})
+				})
+			})
+		})
+	})
+}
+
+func TestUnitFollowRotatedFile(t *testing.T) {
+	Convey("Given a followed file that is rotated", t, func() {
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "data.txt")
+		appendFile(t, path, "old\n")
+
+		r, lines, cancel := openTest(t, path)
+		defer cancel()
+		defer r.Close()
+
+		lines.ReadString('\n')
+
+		appendFile(t, path, "last\n")
+		if err := os.Rename(path, filepath.Join(dir, "data.txt.1")); err != nil {
+			t.Fatal(err)
+		}
+		appendFile(t, path, "rotated\n")
+
+		Convey("When reading to the end of the old file", func() {
+			last, _ := lines.ReadString('\n')
+			_, err := lines.ReadString('\n')
+
+			Convey("The remainder of the old file should be read before io.EOF", func() {
+				So(last, ShouldEqual, "last\n")
+				So(err, ShouldEqual, io.EOF)
+			})
+
+			Convey("And continuing with the next content", func() {
+				So(r.Next(), ShouldBeTrue)
+				line, _ := bufio.NewReader(r).ReadString('\n')
+
+				Convey("The new file should be read", func() {
+					So(line, ShouldEqual, "rotated\n")
+				})
+			})
+		})
+	})
+}